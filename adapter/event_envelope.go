@@ -0,0 +1,143 @@
+package adapter
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// AggregateEvent is implemented by events that know the aggregate that emitted them.
+// Events without it get the aggregate of the entity they were collected from.
+type AggregateEvent interface {
+	AggregateType() string
+	AggregateID() string
+}
+
+// EventEnvelope carries the metadata of a domain event as it flows from the unit of work
+// through the message bus to handlers and the outbox
+type EventEnvelope struct {
+	EventID       string            `json:"event_id"`
+	EventType     string            `json:"event_type,omitempty"`    // Stable name of the event (see eventschema.Name)
+	EventVersion  int               `json:"event_version,omitempty"` // Schema version of the event payload
+	OccurredAt    time.Time         `json:"occurred_at"`
+	AggregateType string            `json:"aggregate_type,omitempty"`
+	AggregateID   string            `json:"aggregate_id,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"` // ID shared by everything caused by one request
+	CausationID   string            `json:"causation_id,omitempty"`   // ID of the command or event that caused this event
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// NewEventEnvelope creates an envelope for event with a new event ID, taking correlation
+// and causation IDs from ctx. When ctx has no correlation ID the chain starts here and
+// the causation ID (or the event ID itself) is used. The trace context of ctx is stored
+// in Metadata so handlers running asynchronously (or in another service) join the trace.
+func NewEventEnvelope(ctx context.Context, event any) EventEnvelope {
+	envelope := EventEnvelope{
+		EventID:       uuid.New().String(),
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		CausationID:   CausationIDFromContext(ctx),
+	}
+
+	if event != nil {
+		envelope.EventType = eventschema.Name(event)
+		envelope.EventVersion = eventschema.Version(event)
+	}
+
+	if aggregate, ok := event.(AggregateEvent); ok {
+		envelope.AggregateType = aggregate.AggregateType()
+		envelope.AggregateID = aggregate.AggregateID()
+	}
+
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		carrier := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+		if len(carrier) > 0 {
+			envelope.Metadata = carrier
+		}
+	}
+
+	if envelope.CorrelationID == "" {
+		envelope.CorrelationID = envelope.CausationID
+	}
+	if envelope.CorrelationID == "" {
+		envelope.CorrelationID = envelope.EventID
+	}
+
+	return envelope
+}
+
+// WithAggregate fills the aggregate type and ID from entity unless the event already provided them
+func (e EventEnvelope) WithAggregate(entity Entity) EventEnvelope {
+	if e.AggregateType != "" || entity == nil {
+		return e
+	}
+
+	t := reflect.TypeOf(entity)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	e.AggregateType = t.Name()
+	e.AggregateID = strconv.FormatUint(entity.GetID(), 10)
+	return e
+}
+
+// ContextWithTrace returns ctx joined to the trace stored in the envelope metadata.
+// ctx is returned unchanged when it already carries a span.
+func (e EventEnvelope) ContextWithTrace(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() || len(e.Metadata) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(e.Metadata))
+}
+
+type (
+	envelopeKey      struct{}
+	correlationIDKey struct{}
+	causationIDKey   struct{}
+)
+
+// ContextWithEnvelope returns a context carrying the envelope of the event being handled
+func ContextWithEnvelope(ctx context.Context, envelope EventEnvelope) context.Context {
+	return context.WithValue(ctx, envelopeKey{}, envelope)
+}
+
+// EnvelopeFromContext returns the envelope of the event being handled, if any
+func EnvelopeFromContext(ctx context.Context) (EventEnvelope, bool) {
+	envelope, ok := ctx.Value(envelopeKey{}).(EventEnvelope)
+	return envelope, ok
+}
+
+// ContextWithCorrelationID returns a context carrying the correlation ID, typically the request ID
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, or an empty string
+func CorrelationIDFromContext(ctx context.Context) string {
+	if correlationID, ok := ctx.Value(correlationIDKey{}).(string); ok {
+		return correlationID
+	}
+	return ""
+}
+
+// ContextWithCausationID returns a context carrying the ID of the command or event being handled
+func ContextWithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationIDKey{}, causationID)
+}
+
+// CausationIDFromContext returns the causation ID stored in ctx, or an empty string
+func CausationIDFromContext(ctx context.Context) string {
+	if causationID, ok := ctx.Value(causationIDKey{}).(string); ok {
+		return causationID
+	}
+	return ""
+}
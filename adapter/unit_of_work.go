@@ -4,11 +4,18 @@ import (
 	"context"
 	"sync"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
 	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/types"
 )
 
+const tracerName = "github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+
 type txKey struct{}
 
 type UnitOfWork interface {
@@ -19,9 +26,11 @@ type UnitOfWork interface {
 }
 
 type EventWithWaitGroup struct {
-	Event interface{}
-	Ctx   context.Context
-	Wg    *sync.WaitGroup
+	Event    interface{}
+	Envelope EventEnvelope
+	Ctx      context.Context
+	Wg       *sync.WaitGroup
+	Reject   func(err error) // Called, before Wg.Done, when the event will not be handled or could not be published; the event is dead-lettered by the bus
 }
 
 type BaseUnitOfWork struct {
@@ -49,18 +58,28 @@ func (uow *BaseUnitOfWork) GetSession(ctx context.Context) *gorm.DB {
 }
 
 func (uow *BaseUnitOfWork) Do(ctx context.Context, fc types.UowUseCase) error {
-	uow.clearRepositories()
-
 	if ctx.Value(txKey{}) != nil {
 		return fc(ctx)
 	}
 
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "uow.Do", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
 	// Collect events during transaction, but don't publish them yet
-	var collectedEvents []interface{}
+	var collectedEvents []EventWithWaitGroup
+
+	// Repositories are tracked per transaction context, so concurrent Do calls only ever
+	// clear their own
+	var txCtx context.Context
+	defer func() {
+		uow.clearRepositories(txCtx)
+	}()
 
 	err := uow.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		span.AddEvent("transaction.begin")
+
 		// Store transaction in context so GetSession can retrieve it
-		txCtx := context.WithValue(ctx, txKey{}, tx)
+		txCtx = context.WithValue(ctx, txKey{}, tx)
 		err := fc(txCtx)
 		if err != nil {
 			return err
@@ -84,8 +103,12 @@ func (uow *BaseUnitOfWork) Do(ctx context.Context, fc types.UowUseCase) error {
 		for _, repo := range repos {
 			entities := repo.Seen()
 			for _, entity := range entities {
-				events := entity.Event()
-				collectedEvents = append(collectedEvents, events...)
+				for _, event := range entity.Event() {
+					collectedEvents = append(collectedEvents, EventWithWaitGroup{
+						Event:    event,
+						Envelope: NewEventEnvelope(ctx, event).WithAggregate(entity),
+					})
+				}
 			}
 		}
 
@@ -93,35 +116,66 @@ func (uow *BaseUnitOfWork) Do(ctx context.Context, fc types.UowUseCase) error {
 	})
 
 	if err != nil {
-		uow.clearRepositories()
+		span.AddEvent("transaction.rollback")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
+	span.AddEvent("transaction.commit")
+	span.SetAttributes(attribute.Int("uow.events_dispatched", len(collectedEvents)))
 
+	var rejected error
 	if len(collectedEvents) > 0 {
 		var wg sync.WaitGroup
+		var rejectMu sync.Mutex
+		reject := func(err error) {
+			rejectMu.Lock()
+			defer rejectMu.Unlock()
+			if rejected == nil {
+				rejected = err
+			}
+		}
+
 		for _, event := range collectedEvents {
 			wg.Add(1)
-			eventCtx := context.WithValue(ctx, txKey{}, nil)
+			event.Ctx = context.WithValue(ctx, txKey{}, nil)
+			event.Wg = &wg
+			event.Reject = reject
 			select {
-			case uow.eventCh <- EventWithWaitGroup{Event: event, Ctx: eventCtx, Wg: &wg}:
+			case uow.eventCh <- event:
 				// Event sent with WaitGroup and its own context, will be done when handled
 			case <-ctx.Done():
+				span.RecordError(ctx.Err())
+				span.SetStatus(codes.Error, ctx.Err().Error())
 				wg.Done()
-				uow.clearRepositories()
 				return ctx.Err()
 			}
 		}
 		wg.Wait()
 	}
 
-	uow.clearRepositories()
+	if rejected != nil {
+		// The transaction is committed, so Do succeeded: returning an error would make callers
+		// retry the write. Events the bus did not handle are dead-lettered by the bus.
+		logging.Warn("Transaction committed but some of its events were not handled").
+			WithInt("events", len(collectedEvents)).
+			WithError(rejected).
+			Log()
+		span.AddEvent("events.rejected", trace.WithAttributes(attribute.String("error", rejected.Error())))
+	}
+	span.SetStatus(codes.Ok, "")
 	return nil
 }
 
-func (uow *BaseUnitOfWork) clearRepositories() {
+// clearRepositories forgets the repositories tracked for the transaction context txCtx
+func (uow *BaseUnitOfWork) clearRepositories(txCtx context.Context) {
+	if txCtx == nil {
+		return
+	}
+
 	uow.mu.Lock()
 	defer uow.mu.Unlock()
-	uow.repositories = make(map[context.Context]map[string]SeenedRepository)
+	delete(uow.repositories, txCtx)
 }
 
 func (uow *BaseUnitOfWork) GetOrCreateRepository(
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// HealthChecker is implemented by components that can report their health,
+// such as databases.PoolMonitor, redis and elasticsearch connections
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthStatus is the health of a single component in a readiness response
+type HealthStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// ReadinessHandler returns a handler that runs all checkers concurrently and responds
+// with 200 when every component is healthy, or 503 when at least one is not.
+// If timeout is 0, it defaults to 5 seconds.
+func ReadinessHandler(timeout time.Duration, checkers map[string]HealthChecker) fiber.Handler {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	return func(c fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.Context(), timeout)
+		defer cancel()
+
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			healthy = true
+			results = make(map[string]HealthStatus, len(checkers))
+		)
+
+		for name, checker := range checkers {
+			wg.Add(1)
+			go func(name string, checker HealthChecker) {
+				defer wg.Done()
+
+				status := HealthStatus{Status: HealthStatusUp}
+				if err := checker.HealthCheck(ctx); err != nil {
+					status = HealthStatus{Status: HealthStatusDown, Error: err.Error()}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				results[name] = status
+				if status.Status != HealthStatusUp {
+					healthy = false
+				}
+			}(name, checker)
+		}
+		wg.Wait()
+
+		status := fiber.StatusOK
+		if !healthy {
+			status = fiber.StatusServiceUnavailable
+		}
+
+		return ResJSON(c, status, ResponseResult{
+			Success: healthy,
+			Data:    results,
+		})
+	}
+}
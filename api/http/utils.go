@@ -2,7 +2,6 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"math"
 	"net/http"
 	"reflect"
@@ -10,53 +9,12 @@ import (
 
 	"github.com/ali-mahdavi-dev/shikposh-framework/errors"
 	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+	"github.com/ali-mahdavi-dev/shikposh-framework/helpers/validation"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
 	"github.com/spf13/cast"
 )
 
-var validate *validator.Validate
-
-func init() {
-	validate = validator.New()
-}
-
-// formatValidationError formats validator errors into a readable message
-func formatValidationError(err error) string {
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		var messages []string
-		for _, fieldError := range validationErrors {
-			message := fmt.Sprintf("field '%s' failed validation: %s", fieldError.Field(), getValidationMessage(fieldError))
-			messages = append(messages, message)
-		}
-		return strings.Join(messages, "; ")
-	}
-	return err.Error()
-}
-
-// getValidationMessage returns a user-friendly message for validation errors
-func getValidationMessage(fieldError validator.FieldError) string {
-	switch fieldError.Tag() {
-	case "required":
-		return "is required"
-	case "min":
-		return fmt.Sprintf("must be at least %s characters", fieldError.Param())
-	case "max":
-		return fmt.Sprintf("must be at most %s characters", fieldError.Param())
-	case "email":
-		return "must be a valid email address"
-	case "len":
-		return fmt.Sprintf("must be exactly %s characters", fieldError.Param())
-	case "gte":
-		return fmt.Sprintf("must be greater than or equal to %s", fieldError.Param())
-	case "lte":
-		return fmt.Sprintf("must be less than or equal to %s", fieldError.Param())
-	default:
-		return fmt.Sprintf("failed validation rule '%s'", fieldError.Tag())
-	}
-}
-
 var statusMap = map[string]int{
 	http.StatusText(http.StatusBadRequest):            http.StatusBadRequest,
 	http.StatusText(http.StatusUnauthorized):          http.StatusUnauthorized,
@@ -106,8 +64,8 @@ func ParseJSON(c fiber.Ctx, obj interface{}) error {
 	}
 
 	// Validate struct
-	if err := validate.Struct(obj); err != nil {
-		return errors.Validation(phrases.FailedParseJson, formatValidationError(err))
+	if err := validation.Struct(obj); err != nil {
+		return errors.Validation(phrases.FailedParseJson, validation.FormatError(err))
 	}
 
 	return nil
@@ -120,8 +78,8 @@ func ParseQuery(c fiber.Ctx, obj interface{}) error {
 	}
 
 	// Validate struct
-	if err := validate.Struct(obj); err != nil {
-		return errors.Validation(phrases.FailedParseQuery, formatValidationError(err))
+	if err := validation.Struct(obj); err != nil {
+		return errors.Validation(phrases.FailedParseQuery, validation.FormatError(err))
 	}
 
 	return nil
@@ -144,8 +102,8 @@ func ParseForm(c fiber.Ctx, obj interface{}) error {
 	}
 
 	// Validate struct
-	if err := validate.Struct(obj); err != nil {
-		return errors.Validation(phrases.FailedParseForm, formatValidationError(err))
+	if err := validation.Struct(obj); err != nil {
+		return errors.Validation(phrases.FailedParseForm, validation.FormatError(err))
 	}
 
 	return nil
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+const meterName = "github.com/ali-mahdavi-dev/shikposh-framework/api/middleware"
+
+// MetricsMiddleware records the duration and status of every HTTP request.
+// Requests are labelled with the matched route pattern rather than the raw path
+// to keep the number of series bounded.
+func MetricsMiddleware() fiber.Handler {
+	meter := otel.Meter(meterName)
+
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests"),
+		metric.WithUnit("s"))
+	if err != nil {
+		logging.Warn("Failed to create HTTP duration histogram").WithError(err).Log()
+		duration = noop.Float64Histogram{}
+	}
+	active, err := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("Number of HTTP requests currently being served"))
+	if err != nil {
+		logging.Warn("Failed to create HTTP active requests counter").WithError(err).Log()
+		active = noop.Int64UpDownCounter{}
+	}
+
+	return func(c fiber.Ctx) error {
+		ctx := c.Context()
+		methodAttr := attribute.String("http.request.method", c.Method())
+
+		active.Add(ctx, 1, metric.WithAttributes(methodAttr))
+		start := time.Now()
+
+		err := c.Next()
+
+		active.Add(ctx, -1, metric.WithAttributes(methodAttr))
+
+		status := c.Response().StatusCode()
+		if err != nil && status < fiber.StatusBadRequest {
+			status = fiber.StatusInternalServerError
+		}
+		route := c.Route().Path
+		if route == "" {
+			route = "unmatched"
+		}
+
+		duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			methodAttr,
+			attribute.String("http.route", route),
+			attribute.String("http.response.status_code", strconv.Itoa(status)),
+		))
+
+		return err
+	}
+}
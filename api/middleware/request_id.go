@@ -3,6 +3,8 @@ package middleware
 import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
 )
 
 const (
@@ -13,7 +15,8 @@ const (
 )
 
 // RequestIDMiddleware generates or extracts request ID from headers
-// and stores it in the context for logging and tracing purposes
+// and stores it in the context for logging and tracing purposes.
+// The request ID also becomes the correlation ID of events raised while handling the request.
 func RequestIDMiddleware() fiber.Handler {
 	return func(c fiber.Ctx) error {
 		// Check if request ID already exists in header
@@ -26,6 +29,7 @@ func RequestIDMiddleware() fiber.Handler {
 
 		// Store request ID in Fiber context
 		c.Locals(RequestIDKey, requestID)
+		c.SetContext(adapter.ContextWithCorrelationID(c.Context(), requestID))
 
 		// Add request ID to response header
 		c.Set(RequestIDHeader, requestID)
@@ -64,9 +64,11 @@ func TracingMiddleware() fiber.Handler {
 			span.SetAttributes(attribute.String("request.id", requestID))
 		}
 
-		// Store span in locals for potential use in handlers
+		// Store span in locals for potential use in handlers, and in the request context
+		// so commands, events and the unit of work started from handlers create child spans
 		c.Locals("span", span)
 		c.Locals("trace_ctx", ctx)
+		c.SetContext(ctx)
 
 		// Record start time
 		start := time.Now()
@@ -22,4 +22,14 @@ const (
 	FailedParseJson  MessagePhrase = "FailedParseJson"
 	FailedParseQuery MessagePhrase = "FailedParseQuery"
 	FailedParseForm  MessagePhrase = "FailedParseForm"
+
+	// Framework command errors
+	CommandValidationFailed MessagePhrase = "Command.ValidationFailed"
+	CommandTimeout          MessagePhrase = "Command.Timeout"
+
+	// Framework specification errors
+	SpecificationNotSatisfied     MessagePhrase = "Specification.NotSatisfied"
+	SpecificationUnknownType      MessagePhrase = "Specification.UnknownType"
+	SpecificationInvalidParams    MessagePhrase = "Specification.InvalidParams"
+	SpecificationInvalidStructure MessagePhrase = "Specification.InvalidStructure"
 )
@@ -43,6 +43,13 @@ func (r *Registry) initDefaults() {
 		FailedParseJson:           "خطا در تجزیه JSON: %s",
 		FailedParseQuery:          "خطا در تجزیه Query: %s",
 		FailedParseForm:           "خطا در تجزیه Form: %s",
+		SpecificationNotSatisfied: "شرایط لازم برقرار نیست",
+
+		CommandValidationFailed:       "دستور نامعتبر است: %s",
+		CommandTimeout:                "دستور در مهلت %s انجام نشد",
+		SpecificationUnknownType:      "نوع قاعده ناشناخته است: %s",
+		SpecificationInvalidParams:    "پارامترهای قاعده %s نامعتبر است: %s",
+		SpecificationInvalidStructure: "ساختار قاعده نامعتبر است: %s",
 	}
 
 	r.phrases[En] = map[MessagePhrase]string{
@@ -60,6 +67,13 @@ func (r *Registry) initDefaults() {
 		FailedParseJson:           "Failed to parse json: %s",
 		FailedParseQuery:          "Failed to parse query: %s",
 		FailedParseForm:           "Failed to parse form: %s",
+		SpecificationNotSatisfied: "Required conditions are not satisfied",
+
+		CommandValidationFailed:       "Invalid command: %s",
+		CommandTimeout:                "Command did not complete within %s",
+		SpecificationUnknownType:      "Unknown specification type: %s",
+		SpecificationInvalidParams:    "Invalid parameters for specification %s: %s",
+		SpecificationInvalidStructure: "Invalid specification structure: %s",
 	}
 }
 
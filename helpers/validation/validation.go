@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+)
+
+var validate = validator.New()
+
+// Validatable is implemented by values with validation rules that struct tags cannot express
+type Validatable interface {
+	Validate() error
+}
+
+// Validator returns the shared validator so applications can register custom validations
+func Validator() *validator.Validate {
+	return validate
+}
+
+// Struct validates obj using its `validate` struct tags. Values that are not structs
+// (or pointers to structs) are rejected with a *validator.InvalidValidationError.
+func Struct(obj interface{}) error {
+	return validate.Struct(obj)
+}
+
+// Validate validates obj using its struct tags and then its Validate method, if any.
+// Struct tags of values that are not structs are skipped, so any command can be validated.
+// Failures are returned as validation errors with the given phrase, formatted with
+// the field-level messages. Application errors returned by Validate are passed through.
+func Validate(obj interface{}, id phrases.MessagePhrase) error {
+	if err := Struct(obj); err != nil {
+		if _, ok := err.(*validator.InvalidValidationError); !ok {
+			return errors.Validation(id, FormatError(err))
+		}
+	}
+
+	validatable, ok := obj.(Validatable)
+	if !ok {
+		return nil
+	}
+
+	err := validatable.Validate()
+	if err == nil {
+		return nil
+	}
+	if _, ok := errors.As(err); ok {
+		return err
+	}
+	return errors.Validation(id, FormatError(err))
+}
+
+// FormatError formats validator errors into a readable message
+func FormatError(err error) string {
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		var messages []string
+		for _, fieldError := range validationErrors {
+			message := fmt.Sprintf("field '%s' failed validation: %s", fieldError.Field(), FieldMessage(fieldError))
+			messages = append(messages, message)
+		}
+		return strings.Join(messages, "; ")
+	}
+	return err.Error()
+}
+
+// FieldMessage returns a user-friendly message for a single field validation error
+func FieldMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fieldError.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fieldError.Param())
+	case "email":
+		return "must be a valid email address"
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters", fieldError.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fieldError.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fieldError.Param())
+	default:
+		return fmt.Sprintf("failed validation rule '%s'", fieldError.Tag())
+	}
+}
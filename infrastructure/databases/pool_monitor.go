@@ -0,0 +1,271 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+const poolMonitorMeterName = "github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/databases"
+
+// PoolMonitorConfig holds configuration for the database pool monitor
+type PoolMonitorConfig struct {
+	Name        string        // Logical database name used in logs and metric attributes
+	Interval    time.Duration // Interval between health checks and stats logging
+	PingTimeout time.Duration // Timeout applied to each health check ping
+}
+
+// DefaultPoolMonitorConfig returns default configuration for the pool monitor
+func DefaultPoolMonitorConfig(name string) PoolMonitorConfig {
+	return PoolMonitorConfig{
+		Name:        name,
+		Interval:    15 * time.Second,
+		PingTimeout: 2 * time.Second,
+	}
+}
+
+// PoolMonitor periodically checks database health and exposes connection pool
+// statistics (sql.DBStats) as OpenTelemetry metrics.
+// It implements service_host.Service so it can be managed by the ServiceHost.
+type PoolMonitor struct {
+	sqlDB        *sql.DB
+	config       PoolMonitorConfig
+	registration metric.Registration
+
+	mu        sync.RWMutex
+	lastErr   error
+	lastCheck time.Time
+
+	stopChan chan struct{}
+	doneChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPoolMonitor creates a new pool monitor for the given database and registers its metrics
+func NewPoolMonitor(db *gorm.DB, cfg PoolMonitorConfig) (*PoolMonitor, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying SQL database: %w", err)
+	}
+
+	defaults := DefaultPoolMonitorConfig(cfg.Name)
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaults.Interval
+	}
+	if cfg.PingTimeout <= 0 {
+		cfg.PingTimeout = defaults.PingTimeout
+	}
+
+	m := &PoolMonitor{
+		sqlDB:    sqlDB,
+		config:   cfg,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+
+	if err := m.registerMetrics(); err != nil {
+		return nil, fmt.Errorf("failed to register database pool metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+// registerMetrics registers observable instruments that read sql.DBStats on every collection
+func (m *PoolMonitor) registerMetrics() error {
+	meter := otel.Meter(poolMonitorMeterName)
+
+	maxOpen, err := meter.Int64ObservableGauge("db.pool.max_open_connections",
+		metric.WithDescription("Maximum number of open connections to the database"))
+	if err != nil {
+		return err
+	}
+	open, err := meter.Int64ObservableGauge("db.pool.open_connections",
+		metric.WithDescription("Number of established connections, both in use and idle"))
+	if err != nil {
+		return err
+	}
+	inUse, err := meter.Int64ObservableGauge("db.pool.in_use_connections",
+		metric.WithDescription("Number of connections currently in use"))
+	if err != nil {
+		return err
+	}
+	idle, err := meter.Int64ObservableGauge("db.pool.idle_connections",
+		metric.WithDescription("Number of idle connections"))
+	if err != nil {
+		return err
+	}
+	waitCount, err := meter.Int64ObservableCounter("db.pool.wait_count",
+		metric.WithDescription("Total number of connections waited for"))
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter("db.pool.wait_duration",
+		metric.WithDescription("Total time blocked waiting for a new connection"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+	maxIdleClosed, err := meter.Int64ObservableCounter("db.pool.max_idle_closed",
+		metric.WithDescription("Total number of connections closed due to SetMaxIdleConns"))
+	if err != nil {
+		return err
+	}
+	maxIdleTimeClosed, err := meter.Int64ObservableCounter("db.pool.max_idle_time_closed",
+		metric.WithDescription("Total number of connections closed due to SetConnMaxIdleTime"))
+	if err != nil {
+		return err
+	}
+	maxLifetimeClosed, err := meter.Int64ObservableCounter("db.pool.max_lifetime_closed",
+		metric.WithDescription("Total number of connections closed due to SetConnMaxLifetime"))
+	if err != nil {
+		return err
+	}
+
+	attrs := metric.WithAttributes(attribute.String("db.name", m.config.Name))
+	m.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := m.sqlDB.Stats()
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+		o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+		o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+		o.ObserveInt64(idle, int64(stats.Idle), attrs)
+		o.ObserveInt64(waitCount, stats.WaitCount, attrs)
+		o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), attrs)
+		o.ObserveInt64(maxIdleClosed, stats.MaxIdleClosed, attrs)
+		o.ObserveInt64(maxIdleTimeClosed, stats.MaxIdleTimeClosed, attrs)
+		o.ObserveInt64(maxLifetimeClosed, stats.MaxLifetimeClosed, attrs)
+		return nil
+	}, maxOpen, open, inUse, idle, waitCount, waitDuration, maxIdleClosed, maxIdleTimeClosed, maxLifetimeClosed)
+
+	return err
+}
+
+// Start runs periodic health checks until Shutdown is called
+func (m *PoolMonitor) Start() error {
+	defer close(m.doneChan)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	m.check()
+
+	for {
+		select {
+		case <-m.stopChan:
+			logging.Info("Database pool monitor stopped").
+				WithString("db_name", m.config.Name).
+				Log()
+			return nil
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// Shutdown stops the periodic health checks and unregisters the pool metrics
+func (m *PoolMonitor) Shutdown(ctx context.Context) error {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+		if m.registration != nil {
+			if err := m.registration.Unregister(); err != nil {
+				logging.Warn("Failed to unregister database pool metrics").
+					WithString("db_name", m.config.Name).
+					WithError(err).
+					Log()
+			}
+		}
+	})
+
+	select {
+	case <-m.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Name returns the name of the service for logging purposes
+func (m *PoolMonitor) Name() string {
+	return "database-pool-monitor:" + m.config.Name
+}
+
+// HealthCheck pings the database, bounded by the configured ping timeout
+func (m *PoolMonitor) HealthCheck(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, m.config.PingTimeout)
+	defer cancel()
+
+	if err := m.sqlDB.PingContext(pingCtx); err != nil {
+		return fmt.Errorf("database %s ping failed: %w", m.config.Name, err)
+	}
+	return nil
+}
+
+// Healthy reports the result of the last periodic health check
+func (m *PoolMonitor) Healthy() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.lastCheck.IsZero() && m.lastErr == nil
+}
+
+// LastError returns the error of the last periodic health check, if any
+func (m *PoolMonitor) LastError() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr
+}
+
+// Stats returns the current connection pool statistics
+func (m *PoolMonitor) Stats() sql.DBStats {
+	return m.sqlDB.Stats()
+}
+
+func (m *PoolMonitor) check() {
+	err := m.HealthCheck(context.Background())
+
+	m.mu.Lock()
+	wasHealthy := !m.lastCheck.IsZero() && m.lastErr == nil
+	firstCheck := m.lastCheck.IsZero()
+	m.lastErr = err
+	m.lastCheck = time.Now()
+	m.mu.Unlock()
+
+	stats := m.sqlDB.Stats()
+
+	if err != nil {
+		logging.Error("Database health check failed").
+			WithString("db_name", m.config.Name).
+			WithInt("open_connections", stats.OpenConnections).
+			WithInt("in_use", stats.InUse).
+			WithInt("idle", stats.Idle).
+			WithError(err).
+			Log()
+		return
+	}
+
+	if !wasHealthy && !firstCheck {
+		logging.Info("Database health check recovered").
+			WithString("db_name", m.config.Name).
+			Log()
+	}
+
+	logging.Debug("Database connection pool stats").
+		WithString("db_name", m.config.Name).
+		WithInt("max_open_conns", stats.MaxOpenConnections).
+		WithInt("open_connections", stats.OpenConnections).
+		WithInt("in_use", stats.InUse).
+		WithInt("idle", stats.Idle).
+		WithInt64("wait_count", stats.WaitCount).
+		WithInt64("wait_duration_ms", stats.WaitDuration.Milliseconds()).
+		Log()
+}
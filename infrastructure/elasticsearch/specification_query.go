@@ -0,0 +1,223 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/specification"
+)
+
+// QueryProvider is implemented by custom leaf specifications that know their own
+// Elasticsearch Query DSL clause
+type QueryProvider interface {
+	ElasticsearchQuery() (map[string]interface{}, error)
+}
+
+// QueryTranslator translates specification trees into Elasticsearch Query DSL so the
+// same specification drives both database and search filtering.
+//
+// And, Or and Not become bool must, should and must_not clauses; field specifications
+// become term, terms, range, wildcard and exists queries. Other leaves must implement QueryProvider.
+type QueryTranslator[T any] struct {
+	fieldMapping map[string]string
+}
+
+// NewQueryTranslator creates a translator. fieldMapping renames specification field paths
+// to index field names (e.g. "Status" -> "status.keyword"); unmapped fields are used as-is.
+// String fields used with Eq, NotEq, In or Contains must map to keyword fields: these
+// operators compare the whole unanalyzed value, as they do in memory, and on an analyzed
+// text field they miss values that the analyzer changed (e.g. lowercased).
+func NewQueryTranslator[T any](fieldMapping map[string]string) *QueryTranslator[T] {
+	if fieldMapping == nil {
+		fieldMapping = make(map[string]string)
+	}
+	return &QueryTranslator[T]{fieldMapping: fieldMapping}
+}
+
+// SearchBody returns a search request body ({"query": ...}) ready for Connection.Search
+func (t *QueryTranslator[T]) SearchBody(spec specification.Specification[T]) (map[string]interface{}, error) {
+	query, err := t.Query(spec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"query": query}, nil
+}
+
+// Query translates a specification into a query clause
+func (t *QueryTranslator[T]) Query(spec specification.Specification[T]) (map[string]interface{}, error) {
+	switch s := spec.(type) {
+	case *specification.BuilderSpecification[T]:
+		return t.Query(s.Spec())
+	case *specification.AndSpecification[T]:
+		return t.boolQuery("must", s)
+	case *specification.OrSpecification[T]:
+		query, err := t.boolQuery("should", s)
+		if err != nil {
+			return nil, err
+		}
+		query["bool"].(map[string]interface{})["minimum_should_match"] = 1
+		return query, nil
+	case *specification.NotSpecification[T]:
+		inner, err := t.Query(s.Spec())
+		if err != nil {
+			return nil, err
+		}
+		return boolClause("must_not", inner), nil
+	case QueryProvider:
+		return s.ElasticsearchQuery()
+	case specification.FieldSpecification:
+		return t.fieldQuery(s)
+	case interface {
+		Unwrap() specification.Specification[T]
+	}:
+		return t.Query(s.Unwrap())
+	default:
+		return nil, fmt.Errorf("specification %T cannot be translated to an elasticsearch query", spec)
+	}
+}
+
+// boolQuery flattens nested operands of the same composite type into one bool clause
+func (t *QueryTranslator[T]) boolQuery(occur string, spec specification.Specification[T]) (map[string]interface{}, error) {
+	operands := flattenOperands(spec)
+	clauses := make([]interface{}, 0, len(operands))
+	for _, operand := range operands {
+		clause, err := t.Query(operand)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{occur: clauses},
+	}, nil
+}
+
+// fieldQuery translates a field specification. Eq, NotEq and In become term and terms
+// queries, which match the exact indexed value.
+func (t *QueryTranslator[T]) fieldQuery(spec specification.FieldSpecification) (map[string]interface{}, error) {
+	field := spec.Field()
+	if mapped, ok := t.fieldMapping[field]; ok {
+		field = mapped
+	}
+	values := spec.Values()
+
+	single := func() (interface{}, error) {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("operator %s on %s expects 1 value, got %d", spec.Operator(), field, len(values))
+		}
+		return values[0], nil
+	}
+
+	switch spec.Operator() {
+	case specification.FieldEq, specification.FieldNotEq:
+		value, err := single()
+		if err != nil {
+			return nil, err
+		}
+		term := map[string]interface{}{"term": map[string]interface{}{field: value}}
+		if spec.Operator() == specification.FieldNotEq {
+			return boolClause("must_not", term), nil
+		}
+		return term, nil
+	case specification.FieldIn:
+		return map[string]interface{}{"terms": map[string]interface{}{field: values}}, nil
+	case specification.FieldBetween:
+		if len(values) != 2 {
+			return nil, fmt.Errorf("operator %s on %s expects 2 values, got %d", spec.Operator(), field, len(values))
+		}
+		return rangeClause(field, map[string]interface{}{"gte": values[0], "lte": values[1]}), nil
+	case specification.FieldGreaterThan, specification.FieldGreaterThanOrEqual,
+		specification.FieldLessThan, specification.FieldLessThanOrEqual:
+		value, err := single()
+		if err != nil {
+			return nil, err
+		}
+		return rangeClause(field, map[string]interface{}{string(spec.Operator()): value}), nil
+	case specification.FieldContains:
+		value, err := single()
+		if err != nil {
+			return nil, err
+		}
+		return containsQuery[T](spec.Field(), field, value)
+	case specification.FieldIsNull:
+		return boolClause("must_not", map[string]interface{}{
+			"exists": map[string]interface{}{"field": field},
+		}), nil
+	default:
+		return nil, fmt.Errorf("field operator %s is not supported by elasticsearch translation", spec.Operator())
+	}
+}
+
+// containsQuery translates Contains with the semantics of the in-memory check: substring
+// matching for string fields (a wildcard query) and element membership for slice fields
+// (a term query, which matches any element of an array field)
+func containsQuery[T any](path, field string, value interface{}) (map[string]interface{}, error) {
+	fieldType, ok := specification.FieldType[T](path)
+	if !ok {
+		return nil, fmt.Errorf("operator %s on %s cannot be translated: the field type is unknown", specification.FieldContains, path)
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		substring, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s on string field %s expects a string, got %T", specification.FieldContains, path, value)
+		}
+		return map[string]interface{}{
+			"wildcard": map[string]interface{}{
+				field: map[string]interface{}{"value": "*" + wildcardEscaper.Replace(substring) + "*"},
+			},
+		}, nil
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"term": map[string]interface{}{field: value}}, nil
+	default:
+		return nil, fmt.Errorf("operator %s on %s cannot be translated: %s is neither a string nor a slice", specification.FieldContains, path, fieldType)
+	}
+}
+
+// wildcardEscaper escapes the characters with a special meaning in wildcard patterns
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// flattenOperands collects the operands of nested And (or nested Or) specifications
+func flattenOperands[T any](spec specification.Specification[T]) []specification.Specification[T] {
+	switch s := spec.(type) {
+	case *specification.AndSpecification[T]:
+		return append(flattenAnd(s.Left()), flattenAnd(s.Right())...)
+	case *specification.OrSpecification[T]:
+		return append(flattenOr(s.Left()), flattenOr(s.Right())...)
+	}
+	return []specification.Specification[T]{spec}
+}
+
+func flattenAnd[T any](spec specification.Specification[T]) []specification.Specification[T] {
+	if b, ok := spec.(*specification.BuilderSpecification[T]); ok {
+		spec = b.Spec()
+	}
+	if _, ok := spec.(*specification.AndSpecification[T]); ok {
+		return flattenOperands(spec)
+	}
+	return []specification.Specification[T]{spec}
+}
+
+func flattenOr[T any](spec specification.Specification[T]) []specification.Specification[T] {
+	if b, ok := spec.(*specification.BuilderSpecification[T]); ok {
+		spec = b.Spec()
+	}
+	if _, ok := spec.(*specification.OrSpecification[T]); ok {
+		return flattenOperands(spec)
+	}
+	return []specification.Specification[T]{spec}
+}
+
+func boolClause(occur string, clause map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{occur: []interface{}{clause}},
+	}
+}
+
+func rangeClause(field string, bounds map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"range": map[string]interface{}{field: bounds},
+	}
+}
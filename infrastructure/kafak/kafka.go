@@ -7,17 +7,20 @@ import (
 	"sync"
 
 	"github.com/IBM/sarama"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
 )
 
 var Service = newKafkaService([]string{"0.0.0.0:29092"})
 
 // kafkaService struct
 type kafkaService struct {
-	Brokers   []string
-	Producer  sarama.SyncProducer
-	Consumer  sarama.Consumer
-	Topic     string
-	waitGroup sync.WaitGroup
+	Brokers  []string
+	Producer sarama.SyncProducer
+	Consumer sarama.Consumer
+	Topic    string
+
+	producerMu sync.Mutex // Guards Producer, which SendRawMessage creates on first use
 }
 
 // newKafkaService initializes kafkaService
@@ -67,6 +70,80 @@ func (k *kafkaService) SendMessage(topic string, message interface{}) error {
 	return nil
 }
 
+// SendRawMessage sends an already encoded message to Kafka. Messages with the same key
+// are written to the same partition, so they are consumed in order. The producer is created
+// on the first call and reused until Close.
+func (k *kafkaService) SendRawMessage(topic string, key string, value []byte) error {
+	producer, err := k.producer()
+	if err != nil {
+		return err
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(value),
+	}
+	if key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+
+	partition, offset, err := producer.SendMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %v", err)
+	}
+
+	logging.Debug("Kafka message sent").
+		WithString("topic", topic).
+		WithInt("partition", int(partition)).
+		WithInt64("offset", offset).
+		Log()
+	return nil
+}
+
+// producer returns the producer shared by SendRawMessage calls, creating it if needed
+func (k *kafkaService) producer() (sarama.SyncProducer, error) {
+	k.producerMu.Lock()
+	defer k.producerMu.Unlock()
+
+	if k.Producer != nil {
+		return k.Producer, nil
+	}
+
+	producerConfig := sarama.NewConfig()
+	producerConfig.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(k.Brokers, producerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %v", err)
+	}
+	k.Producer = producer
+	return producer, nil
+}
+
+// Close closes the producer created by SendRawMessage, if any
+func (k *kafkaService) Close() error {
+	k.producerMu.Lock()
+	defer k.producerMu.Unlock()
+
+	if k.Producer == nil {
+		return nil
+	}
+	err := k.Producer.Close()
+	k.Producer = nil
+	return err
+}
+
+// NewConsumerGroup creates a member of the consumer group groupID. A group without committed
+// offsets starts at the newest messages; afterwards it resumes from its committed offsets.
+func (k *kafkaService) NewConsumerGroup(groupID string) (sarama.ConsumerGroup, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	group, err := sarama.NewConsumerGroup(k.Brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %v", err)
+	}
+	return group, nil
+}
+
 // ConsumeMessages continuously listens to Kafka topic
 func (k *kafkaService) ConsumeMessages(topic string, fn func(pc sarama.PartitionConsumer)) error {
 	config := sarama.NewConfig()
@@ -86,17 +163,19 @@ func (k *kafkaService) ConsumeMessages(topic string, fn func(pc sarama.Partition
 		return fmt.Errorf("failed to get partitions: %v", err)
 	}
 
+	// Each call waits only for its own partitions, not for other consumers of the service
+	var waitGroup sync.WaitGroup
 	for _, partition := range partitions {
 		pc, err := consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
 		if err != nil {
 			return fmt.Errorf("failed to consume partition: %v", err)
 		}
-		k.waitGroup.Add(1)
+		waitGroup.Add(1)
 		go func(fc func(pc sarama.PartitionConsumer), pc sarama.PartitionConsumer) {
-			defer k.waitGroup.Done()
+			defer waitGroup.Done()
 			fc(pc)
 		}(fn, pc)
 	}
-	k.waitGroup.Wait()
+	waitGroup.Wait()
 	return nil
 }
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// DurationBuckets are the histogram boundaries, in seconds, used for every duration instrument
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type Config struct {
+	ServiceName    string
+	Environment    string
+	Namespace      string // Optional prefix added to every Prometheus metric name
+	RuntimeMetrics bool   // Also expose Go runtime and process metrics
+	Enabled        bool
+}
+
+// Metrics owns the OpenTelemetry meter provider and the Prometheus registry it is exported to.
+// Instruments created through otel.Meter anywhere in the framework (HTTP, bus, outbox, cache,
+// database pool) are exposed by Handler in the Prometheus text format.
+type Metrics struct {
+	provider *sdkmetric.MeterProvider
+	registry *prometheus.Registry
+}
+
+// New initializes metrics and sets the global meter provider
+func New(cfg Config) (*Metrics, error) {
+	if !cfg.Enabled {
+		return &Metrics{}, nil
+	}
+
+	registry := prometheus.NewRegistry()
+	if cfg.RuntimeMetrics {
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	opts := []otelprometheus.Option{otelprometheus.WithRegisterer(registry)}
+	if cfg.Namespace != "" {
+		opts = append(opts, otelprometheus.WithNamespace(cfg.Namespace))
+	}
+	exporter, err := otelprometheus.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+			semconv.DeploymentEnvironmentKey.String(cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+		// Default boundaries are meant for milliseconds; duration instruments are in seconds
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Kind: sdkmetric.InstrumentKindHistogram, Unit: "s"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: DurationBuckets}},
+		)),
+	)
+
+	// Set global meter provider
+	otel.SetMeterProvider(provider)
+
+	logging.Info("Prometheus metrics initialized").
+		WithString("service_name", cfg.ServiceName).
+		WithString("namespace", cfg.Namespace).
+		WithBool("runtime_metrics", cfg.RuntimeMetrics).
+		Log()
+
+	return &Metrics{
+		provider: provider,
+		registry: registry,
+	}, nil
+}
+
+// Meter returns a meter of the global meter provider
+func (m *Metrics) Meter(name string) metric.Meter {
+	return otel.Meter(name)
+}
+
+// HTTPHandler returns a net/http handler serving the metrics in the Prometheus format
+func (m *Metrics) HTTPHandler() http.Handler {
+	if m.registry == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Handler returns a Fiber handler serving the metrics, e.g. app.Get("/metrics", m.Handler())
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(m.HTTPHandler())
+}
+
+// Shutdown flushes and shuts down the meter provider
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	if m.provider != nil {
+		return m.provider.Shutdown(ctx)
+	}
+	return nil
+}
@@ -58,6 +58,13 @@ func (r *connection) PushValues(ctx context.Context, key string, values []string
 	return nil
 }
 
+// PubSubConnection extends Connection with publish/subscribe on channels
+type PubSubConnection interface {
+	Connection
+	Publish(ctx context.Context, channel string, message any) error
+	Subscribe(ctx context.Context, channel string) *redis.PubSub
+}
+
 func (r *connection) Publish(ctx context.Context, channel string, message any) error {
 	if err := r.client.Publish(ctx, channel, message).Err(); err != nil {
 		return fmt.Errorf("connection.Publish fail to publish: %w", err)
@@ -80,3 +87,111 @@ func (r *connection) GetAllListValues(ctx context.Context, key string) ([]string
 func (r *connection) HealthCheck(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
+
+// QueueConnection extends Connection with the list and sorted set operations used by queues
+type QueueConnection interface {
+	Connection
+	PushValues(ctx context.Context, key string, values []string, exp time.Duration) error
+	PopValue(ctx context.Context, key string) (string, error)
+	AddScheduled(ctx context.Context, key string, member string, at time.Time) error
+	MoveDue(ctx context.Context, key string, listKey string, until time.Time, limit int64) (int64, error)
+	LeaseValues(ctx context.Context, listKey string, leaseKey string, now time.Time, until time.Time, limit int64) ([]string, error)
+	ExtendLease(ctx context.Context, key string, member string, until time.Time) (bool, error)
+	RemoveScheduled(ctx context.Context, key string, member string) error
+}
+
+// PopValue removes and returns the oldest value pushed with PushValues.
+// It returns redis.Nil when the list is empty.
+func (r *connection) PopValue(ctx context.Context, key string) (string, error) {
+	return r.client.RPop(ctx, key).Result()
+}
+
+// AddScheduled adds member to the sorted set key, scored by the time it becomes due
+func (r *connection) AddScheduled(ctx context.Context, key string, member string, at time.Time) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: member}).Err()
+}
+
+var moveDueScript = redis.NewScript(`
+local members = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, member in ipairs(members) do
+	redis.call("ZREM", KEYS[1], member)
+	redis.call("LPUSH", KEYS[2], member)
+end
+return #members`)
+
+// MoveDue atomically moves up to limit members of the sorted set key that are due at until
+// onto the list listKey, in the order PopValue returns them, and returns how many were moved
+func (r *connection) MoveDue(ctx context.Context, key string, listKey string, until time.Time, limit int64) (int64, error) {
+	return moveDueScript.Run(ctx, r.client, []string{key, listKey}, until.UnixMilli(), limit).Int64()
+}
+
+var leaseValuesScript = redis.NewScript(`
+local limit = tonumber(ARGV[3])
+local leased = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, limit)
+for _, member in ipairs(leased) do
+	redis.call("ZADD", KEYS[2], ARGV[2], member)
+end
+while #leased < limit do
+	local member = redis.call("RPOP", KEYS[1])
+	if not member then
+		break
+	end
+	redis.call("ZADD", KEYS[2], ARGV[2], member)
+	table.insert(leased, member)
+end
+return leased`)
+
+// LeaseValues atomically leases up to limit values until the given time: values whose lease
+// in the sorted set leaseKey expired at now come first, then values popped from the list
+// listKey. Leased values stay in leaseKey until RemoveScheduled removes them, so a value is
+// never lost when its holder fails before finishing with it.
+func (r *connection) LeaseValues(ctx context.Context, listKey string, leaseKey string, now time.Time, until time.Time, limit int64) ([]string, error) {
+	return leaseValuesScript.Run(ctx, r.client, []string{listKey, leaseKey}, now.UnixMilli(), until.UnixMilli(), limit).StringSlice()
+}
+
+// ExtendLease moves the lease of member in the sorted set key to until and reports whether
+// member was still leased
+func (r *connection) ExtendLease(ctx context.Context, key string, member string, until time.Time) (bool, error) {
+	changed, err := r.client.ZAddArgs(ctx, key, redis.ZAddArgs{
+		XX:      true,
+		Ch:      true,
+		Members: []redis.Z{{Score: float64(until.UnixMilli()), Member: member}},
+	}).Result()
+	if err != nil {
+		return false, err
+	}
+	return changed == 1, nil
+}
+
+// RemoveScheduled removes member from the sorted set key
+func (r *connection) RemoveScheduled(ctx context.Context, key string, member string) error {
+	return r.client.ZRem(ctx, key, member).Err()
+}
+
+// LockConnection extends Connection with the conditional writes used by distributed locks
+type LockConnection interface {
+	Connection
+	SetValueIfAbsent(ctx context.Context, key string, value string, exp time.Duration) (bool, error)
+	DeleteKeyIfValue(ctx context.Context, key string, value string) (bool, error)
+}
+
+// SetValueIfAbsent sets key to value only when key does not exist and reports whether it was set
+func (r *connection) SetValueIfAbsent(ctx context.Context, key string, value string, exp time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, exp).Result()
+}
+
+var deleteIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// DeleteKeyIfValue deletes key only while it still holds value, so a lock is never released
+// by an owner whose lease already expired, and reports whether it was deleted
+func (r *connection) DeleteKeyIfValue(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := deleteIfValueScript.Run(ctx, r.client, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted == 1, nil
+}
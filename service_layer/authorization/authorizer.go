@@ -0,0 +1,113 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// Config holds configuration for the authorizer
+type Config struct {
+	// AllowUnregistered allows commands without registered policies; by default they are denied
+	AllowUnregistered bool
+	// Anonymous lists command type names that may be handled without a principal
+	Anonymous []string
+}
+
+// DefaultConfig returns default configuration for the authorizer
+func DefaultConfig() Config {
+	return Config{
+		AllowUnregistered: false,
+	}
+}
+
+// Authorizer holds the policies registered per command type and checks them before
+// commands are handled, whatever transport the command came from
+type Authorizer struct {
+	config    Config
+	policies  map[string][]Policy
+	anonymous map[string]bool
+	mu        sync.RWMutex
+}
+
+// NewAuthorizer creates an authorizer without policies
+func NewAuthorizer(cfg Config) *Authorizer {
+	anonymous := make(map[string]bool, len(cfg.Anonymous))
+	for _, name := range cfg.Anonymous {
+		anonymous[name] = true
+	}
+
+	return &Authorizer{
+		config:    cfg,
+		policies:  make(map[string][]Policy),
+		anonymous: anonymous,
+	}
+}
+
+// Register adds policies for the command type Command. Every registered policy must allow
+// a command for it to be handled.
+func Register[Command any](a *Authorizer, policies ...Policy) {
+	a.RegisterByName(reflect.TypeOf(new(Command)).String(), policies...)
+}
+
+// RegisterByName adds policies for the command type name as used by the message bus
+func (a *Authorizer) RegisterByName(cmdName string, policies ...Policy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.policies[cmdName] = append(a.policies[cmdName], policies...)
+}
+
+// Authorize checks the policies of cmd against the principal in ctx. It returns an
+// unauthorized error when a principal is required but missing, and a forbidden error
+// when a policy rejects the principal. Commands dispatched by the system principal (see
+// WithSystemPrincipal) are always allowed.
+func (a *Authorizer) Authorize(ctx context.Context, cmd any) error {
+	cmdName := reflect.TypeOf(cmd).String()
+
+	a.mu.RLock()
+	policies := a.policies[cmdName]
+	a.mu.RUnlock()
+
+	principal, authenticated := PrincipalFromContext(ctx)
+	if authenticated && principal.System {
+		return nil
+	}
+
+	if len(policies) == 0 {
+		if !a.config.AllowUnregistered && !a.anonymous[cmdName] {
+			return a.deny(cmdName, principal, authenticated, "no policy registered")
+		}
+		return nil
+	}
+
+	if !authenticated && !a.anonymous[cmdName] {
+		return a.deny(cmdName, principal, authenticated, "principal required")
+	}
+
+	for _, policy := range policies {
+		if !policy.Allows(ctx, principal, cmd) {
+			return a.deny(cmdName, principal, authenticated, fmt.Sprintf("policy %s denied", policy.Name()))
+		}
+	}
+
+	return nil
+}
+
+func (a *Authorizer) deny(cmdName string, principal *Principal, authenticated bool, reason string) error {
+	entry := logging.Warn("Command authorization denied").
+		WithAny("command_name", cmdName).
+		WithString("reason", reason)
+
+	if !authenticated {
+		entry.Log()
+		return apperrors.Unauthorized("")
+	}
+
+	entry.WithString("principal_id", principal.ID).Log()
+	return apperrors.Forbidden("")
+}
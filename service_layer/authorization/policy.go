@@ -0,0 +1,91 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/specification"
+)
+
+// Policy decides whether a principal may handle a command
+type Policy interface {
+	// Name describes the policy in logs, e.g. "roles(admin)"
+	Name() string
+	// Allows reports whether principal may handle cmd
+	Allows(ctx context.Context, principal *Principal, cmd any) bool
+}
+
+// PolicyFunc adapts a function to the Policy interface
+type PolicyFunc struct {
+	name  string
+	allow func(ctx context.Context, principal *Principal, cmd any) bool
+}
+
+// NewPolicy creates a named policy from a function
+func NewPolicy(name string, allow func(ctx context.Context, principal *Principal, cmd any) bool) Policy {
+	return &PolicyFunc{name: name, allow: allow}
+}
+
+func (p *PolicyFunc) Name() string { return p.name }
+
+func (p *PolicyFunc) Allows(ctx context.Context, principal *Principal, cmd any) bool {
+	return p.allow(ctx, principal, cmd)
+}
+
+// Authenticated allows any authenticated principal
+func Authenticated() Policy {
+	return NewPolicy("authenticated", func(_ context.Context, principal *Principal, _ any) bool {
+		return principal != nil
+	})
+}
+
+// Roles allows principals having at least one of the given roles
+func Roles(roles ...string) Policy {
+	return NewPolicy(fmt.Sprintf("roles%v", roles), func(_ context.Context, principal *Principal, _ any) bool {
+		return principal.HasAnyRole(roles...)
+	})
+}
+
+// Permissions allows principals having every given permission
+func Permissions(permissions ...string) Policy {
+	return NewPolicy(fmt.Sprintf("permissions%v", permissions), func(_ context.Context, principal *Principal, _ any) bool {
+		return principal.HasAllPermissions(permissions...)
+	})
+}
+
+// Request is the entity evaluated by specification policies
+type Request[Command any] struct {
+	Principal *Principal
+	Command   *Command
+}
+
+// Specification allows requests satisfying spec, e.g. "the principal owns the order in the command"
+func Specification[Command any](name string, spec specification.Specification[Request[Command]]) Policy {
+	return NewPolicy(name, func(_ context.Context, principal *Principal, cmd any) bool {
+		command, ok := cmd.(*Command)
+		if !ok {
+			return false
+		}
+		return spec.IsSatisfiedBy(Request[Command]{Principal: principal, Command: command})
+	})
+}
+
+// AnyOf allows requests allowed by at least one of the policies
+func AnyOf(policies ...Policy) Policy {
+	return NewPolicy(fmt.Sprintf("any_of%v", policyNames(policies)), func(ctx context.Context, principal *Principal, cmd any) bool {
+		for _, policy := range policies {
+			if policy.Allows(ctx, principal, cmd) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func policyNames(policies []Policy) []string {
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		names = append(names, policy.Name())
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+package authorization
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal is the authenticated identity on whose behalf a command is handled
+type Principal struct {
+	ID          string
+	Roles       []string
+	Permissions []string
+	Attributes  map[string]any // Additional claims, e.g. tenant or organisation IDs
+	System      bool           `json:"system,omitempty"` // Set by WithSystemPrincipal; never set it from caller input
+}
+
+// HasRole reports whether the principal has the given role
+func (p *Principal) HasRole(role string) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+// HasAnyRole reports whether the principal has at least one of the given roles
+func (p *Principal) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the principal has the given permission
+func (p *Principal) HasPermission(permission string) bool {
+	return p != nil && slices.Contains(p.Permissions, permission)
+}
+
+// HasAllPermissions reports whether the principal has every given permission
+func (p *Principal) HasAllPermissions(permissions ...string) bool {
+	for _, permission := range permissions {
+		if !p.HasPermission(permission) {
+			return false
+		}
+	}
+	return true
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a context carrying the principal. Transports (HTTP, websocket,
+// consumers) set it after authenticating the caller, before dispatching commands.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// SystemPrincipalID is the ID of the principal set by WithSystemPrincipal
+const SystemPrincipalID = "system"
+
+// WithSystemPrincipal returns a context carrying the system principal, which every policy
+// check allows. The framework's own dispatchers (the scheduler and saga steps) use it for
+// the commands they send on nobody's behalf.
+func WithSystemPrincipal(ctx context.Context) context.Context {
+	return ContextWithPrincipal(ctx, &Principal{ID: SystemPrincipalID, System: true})
+}
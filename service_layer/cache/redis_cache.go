@@ -7,11 +7,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
 	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/redisx"
 	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/types"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 )
 
 type Store interface {
@@ -22,12 +26,31 @@ type Store interface {
 	DeleteKey(ctx context.Context, key string) error
 }
 
+const meterName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/cache"
+
 type RedisStore struct {
-	store redisx.Connection
+	store  redisx.Connection
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
 }
 
 func NewRedisStore(store redisx.Connection) Store {
-	return &RedisStore{store: store}
+	meter := otel.Meter(meterName)
+
+	hits, err := meter.Int64Counter("cache.hits",
+		metric.WithDescription("Number of cache lookups that found a value"))
+	if err != nil {
+		logging.Warn("Failed to create cache hit counter").WithError(err).Log()
+		hits = noop.Int64Counter{}
+	}
+	misses, err := meter.Int64Counter("cache.misses",
+		metric.WithDescription("Number of cache lookups that found no value"))
+	if err != nil {
+		logging.Warn("Failed to create cache miss counter").WithError(err).Log()
+		misses = noop.Int64Counter{}
+	}
+
+	return &RedisStore{store: store, hits: hits, misses: misses}
 }
 func (r *RedisStore) CreateKey(key ...interface{}) string {
 	output := make([]string, len(key))
@@ -51,9 +74,14 @@ func (r *RedisStore) Cache(ctx context.Context, key string, value interface{}, e
 
 func (r *RedisStore) GetValue(ctx context.Context, key string, value interface{}) error {
 	cached, err := r.store.GetValue(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		r.misses.Add(ctx, 1)
+	}
 	if err != nil {
 		return err
 	}
+	r.hits.Add(ctx, 1)
+
 	if err = json.Unmarshal([]byte(cached), value); err != nil {
 		return err
 	}
@@ -0,0 +1,206 @@
+package catalogue
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// AsyncAPIVersion is the version of the AsyncAPI specification documents are generated for
+const AsyncAPIVersion = "3.0.0"
+
+// AsyncAPIConfig holds the service information and the outbox topics of an AsyncAPI document
+type AsyncAPIConfig struct {
+	Title       string
+	Version     string
+	Description string
+	Topics      []Topic // Broker topics the outbox publishes to
+}
+
+// Topic is a broker topic the outbox processor publishes events to
+type Topic struct {
+	Name        string
+	Description string
+	Events      []TopicEvent
+}
+
+// TopicEvent is an event published to a topic, identified by the outbox event type
+type TopicEvent struct {
+	EventType string // Event type stored in the outbox, sent as event_type
+	Payload   any    // Value of the payload type, e.g. &OrderPlaced{}
+}
+
+// AsyncAPIDocument is an AsyncAPI 3.0 document. Marshal it with encoding/json; the output
+// is also valid YAML.
+type AsyncAPIDocument struct {
+	AsyncAPI           string                        `json:"asyncapi"`
+	Info               AsyncAPIInfo                  `json:"info"`
+	DefaultContentType string                        `json:"defaultContentType"`
+	Channels           map[string]*AsyncAPIChannel   `json:"channels"`
+	Operations         map[string]*AsyncAPIOperation `json:"operations"`
+	Components         AsyncAPIComponents            `json:"components"`
+}
+
+type AsyncAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type AsyncAPIChannel struct {
+	Address     string                 `json:"address"`
+	Description string                 `json:"description,omitempty"`
+	Messages    map[string]AsyncAPIRef `json:"messages"`
+}
+
+type AsyncAPIOperation struct {
+	Action   string        `json:"action"` // "send" or "receive"
+	Channel  AsyncAPIRef   `json:"channel"`
+	Summary  string        `json:"summary,omitempty"`
+	Messages []AsyncAPIRef `json:"messages"`
+}
+
+type AsyncAPIComponents struct {
+	Messages map[string]*AsyncAPIMessage `json:"messages"`
+	Schemas  map[string]*Schema          `json:"schemas"`
+}
+
+type AsyncAPIMessage struct {
+	Name        string  `json:"name"`
+	Title       string  `json:"title,omitempty"`
+	Summary     string  `json:"summary,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Payload     *Schema `json:"payload"`
+}
+
+type AsyncAPIRef struct {
+	Ref string `json:"$ref"`
+}
+
+// AsyncAPI generates an AsyncAPI document describing the commands and events the service
+// receives through its message bus and the events its outbox publishes to broker topics
+func AsyncAPI(c Catalogue, cfg AsyncAPIConfig) *AsyncAPIDocument {
+	doc := &AsyncAPIDocument{
+		AsyncAPI: AsyncAPIVersion,
+		Info: AsyncAPIInfo{
+			Title:       cfg.Title,
+			Version:     cfg.Version,
+			Description: cfg.Description,
+		},
+		DefaultContentType: "application/json",
+		Channels:           map[string]*AsyncAPIChannel{},
+		Operations:         map[string]*AsyncAPIOperation{},
+		Components: AsyncAPIComponents{
+			Messages: map[string]*AsyncAPIMessage{},
+			Schemas:  map[string]*Schema{},
+		},
+	}
+
+	for _, message := range c.Commands {
+		doc.addBusMessage(message, "commands")
+	}
+	for _, message := range c.Events {
+		doc.addBusMessage(message, "events")
+	}
+	for _, topic := range cfg.Topics {
+		doc.addTopic(topic)
+	}
+
+	return doc
+}
+
+// JSON returns the indented JSON encoding of the document
+func (d *AsyncAPIDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// addBusMessage describes a message handled in-process as a channel the service receives on
+func (d *AsyncAPIDocument) addBusMessage(message Message, prefix string) {
+	id := componentID(message.Name)
+	channelID := prefix + "." + id
+
+	d.Components.Schemas[id] = message.Payload
+	d.Components.Messages[id] = &AsyncAPIMessage{
+		Name:    message.Name,
+		Title:   id,
+		Summary: "Handled by " + strings.Join(message.Handlers, ", "),
+		Payload: &Schema{Ref: "#/components/schemas/" + id},
+	}
+	d.Channels[channelID] = &AsyncAPIChannel{
+		Address:  message.Name,
+		Messages: map[string]AsyncAPIRef{id: {Ref: "#/components/messages/" + id}},
+	}
+	d.Operations["receive."+channelID] = &AsyncAPIOperation{
+		Action:   "receive",
+		Channel:  AsyncAPIRef{Ref: "#/channels/" + channelID},
+		Summary:  string(message.Kind) + " " + message.Name,
+		Messages: []AsyncAPIRef{{Ref: "#/channels/" + channelID + "/messages/" + id}},
+	}
+}
+
+// addTopic describes an outbox topic as a channel the service sends on. Messages are the
+// envelopes written by the outbox processor, with the event in payload.
+func (d *AsyncAPIDocument) addTopic(topic Topic) {
+	channelID := "topics." + componentID(topic.Name)
+	channel := &AsyncAPIChannel{
+		Address:     topic.Name,
+		Description: topic.Description,
+		Messages:    map[string]AsyncAPIRef{},
+	}
+	operation := &AsyncAPIOperation{
+		Action:  "send",
+		Channel: AsyncAPIRef{Ref: "#/channels/" + channelID},
+		Summary: "Events published through the outbox",
+	}
+
+	for _, event := range topic.Events {
+		payloadID := componentID(reflect.TypeOf(event.Payload).String())
+		if _, exists := d.Components.Schemas[payloadID]; !exists {
+			d.Components.Schemas[payloadID] = SchemaOf(reflect.TypeOf(event.Payload))
+		}
+
+		id := "outbox." + componentID(event.EventType)
+		d.Components.Messages[id] = &AsyncAPIMessage{
+			Name:    event.EventType,
+			Title:   event.EventType,
+			Payload: outboxEnvelope(event.EventType, eventschema.CurrentVersion(event.EventType), &Schema{Ref: "#/components/schemas/" + payloadID}),
+		}
+		channel.Messages[id] = AsyncAPIRef{Ref: "#/components/messages/" + id}
+		operation.Messages = append(operation.Messages, AsyncAPIRef{Ref: "#/channels/" + channelID + "/messages/" + id})
+	}
+
+	d.Channels[channelID] = channel
+	d.Operations["send."+channelID] = operation
+}
+
+// outboxEnvelope returns the schema of the messages sent by the outbox processor
+func outboxEnvelope(eventType string, version int, payload *Schema) *Schema {
+	str := func() *Schema { return &Schema{Type: "string"} }
+	dateTime := func() *Schema { return &Schema{Type: "string", Format: "date-time"} }
+
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"event_id":          {Type: "integer", Description: "ID of the outbox row"},
+			"envelope_event_id": {Type: "string", Description: "ID of the event envelope"},
+			"event_type":        {Type: "string", Const: eventType},
+			"event_version":     {Type: "integer", Const: version},
+			"aggregate_type":    str(),
+			"aggregate_id":      str(),
+			"correlation_id":    str(),
+			"causation_id":      str(),
+			"metadata":          {Type: "object", AdditionalProperties: str()},
+			"payload":           payload,
+			"occurred_at":       dateTime(),
+			"created_at":        dateTime(),
+		},
+		Required: []string{"event_id", "event_type", "payload"},
+	}
+}
+
+// componentID turns a message name such as "*orders.OrderPlaced" into a component ID
+func componentID(name string) string {
+	return strings.NewReplacer("*", "", "[", "_", "]", "", " ", "_", "/", "_").Replace(name)
+}
@@ -0,0 +1,61 @@
+package catalogue
+
+import (
+	"reflect"
+)
+
+// MessageKind tells commands and events apart
+type MessageKind string
+
+const (
+	MessageKindCommand MessageKind = "command"
+	MessageKindEvent   MessageKind = "event"
+)
+
+// Message describes a message registered with the message bus
+type Message struct {
+	Name     string       `json:"name"` // Name the message is routed under
+	Kind     MessageKind  `json:"kind"`
+	Version  int          `json:"version,omitempty"` // Schema version of events
+	Handlers []string     `json:"handlers"`
+	Payload  *Schema      `json:"payload"`
+	Type     reflect.Type `json:"-"`
+}
+
+// Catalogue lists the commands and events a service handles
+type Catalogue struct {
+	Commands []Message `json:"commands"`
+	Events   []Message `json:"events"`
+}
+
+// NewMessage describes a message of the type of sample, e.g. the value returned by
+// NewCommand or NewEvent of its handler
+func NewMessage(kind MessageKind, name string, sample any, handlers ...string) Message {
+	t := reflect.TypeOf(sample)
+	return Message{
+		Name:     name,
+		Kind:     kind,
+		Handlers: handlers,
+		Payload:  SchemaOf(t),
+		Type:     t,
+	}
+}
+
+// Command returns the registered command with the given name
+func (c Catalogue) Command(name string) (Message, bool) {
+	return find(c.Commands, name)
+}
+
+// Event returns the registered event with the given name
+func (c Catalogue) Event(name string) (Message, bool) {
+	return find(c.Events, name)
+}
+
+func find(messages []Message, name string) (Message, bool) {
+	for _, message := range messages {
+		if message.Name == name {
+			return message, true
+		}
+	}
+	return Message{}, false
+}
@@ -14,11 +14,17 @@ func NewCommandHandler[Command any](
 ) CommandHandler {
 	return &genericCommandHandler[Command]{
 		handleFunc: handleFunc,
+		name:       funcName(handleFunc),
 	}
 }
 
 type genericCommandHandler[Command any] struct {
 	handleFunc func(ctx context.Context, cmd *Command) error
+	name       string
+}
+
+func (c genericCommandHandler[Command]) HandlerName() string {
+	return c.name
 }
 
 func (c genericCommandHandler[Command]) NewCommand() any {
@@ -0,0 +1,20 @@
+package commandmiddleware
+
+import (
+	"context"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/authorization"
+)
+
+// Authorization creates a middleware that checks the authorizer's policies for every command
+// against the principal in the context, rejecting it before the handler runs
+func Authorization(authorizer *authorization.Authorizer) Middleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) error {
+			if err := authorizer.Authorize(ctx, cmd); err != nil {
+				return err
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
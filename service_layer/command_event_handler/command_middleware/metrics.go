@@ -0,0 +1,70 @@
+package commandmiddleware
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+const meterName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command"
+
+// Metrics creates a middleware that records the number, latency and errors of handled commands
+func Metrics() Middleware {
+	meter := otel.Meter(meterName)
+
+	handled, err := meter.Int64Counter("messaging.command.handled",
+		metric.WithDescription("Number of handled commands"))
+	if err != nil {
+		logging.Warn("Failed to create command counter").WithError(err).Log()
+		handled = noop.Int64Counter{}
+	}
+	failed, err := meter.Int64Counter("messaging.command.errors",
+		metric.WithDescription("Number of commands whose handler returned an error"))
+	if err != nil {
+		logging.Warn("Failed to create command error counter").WithError(err).Log()
+		failed = noop.Int64Counter{}
+	}
+	duration, err := meter.Float64Histogram("messaging.command.duration",
+		metric.WithDescription("Duration of command handling"),
+		metric.WithUnit("s"))
+	if err != nil {
+		logging.Warn("Failed to create command duration histogram").WithError(err).Log()
+		duration = noop.Float64Histogram{}
+	}
+
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) error {
+			nameAttr := attribute.String("messaging.command.name", reflect.TypeOf(cmd).String())
+			start := time.Now()
+
+			err := next(ctx, cmd)
+
+			outcome := "success"
+			if err != nil {
+				outcome = "error"
+				failed.Add(ctx, 1, metric.WithAttributes(nameAttr, attribute.String("error.type", errorType(err))))
+			}
+			attrs := metric.WithAttributes(nameAttr, attribute.String("outcome", outcome))
+			handled.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+
+			return err
+		}
+	}
+}
+
+// errorType returns the application error type of err, or "internal" for other errors
+func errorType(err error) string {
+	if appErr, ok := apperrors.As(err); ok {
+		return string(appErr.Type())
+	}
+	return string(apperrors.ErrorTypeInternal)
+}
@@ -0,0 +1,35 @@
+package commandmiddleware
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// Recovery creates a middleware that converts a panic in a command handler into an
+// internal error and logs it with its stack trace. It should be the outermost middleware.
+func Recovery() Middleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					cmdName := reflect.TypeOf(cmd).String()
+
+					logging.Error("Command handler panicked").
+						WithAny("command_name", cmdName).
+						WithAny("panic", r).
+						WithString("stack", string(debug.Stack())).
+						Log()
+
+					err = apperrors.Internal(fmt.Sprintf("panic while handling %s: %v", cmdName, r))
+				}
+			}()
+
+			return next(ctx, cmd)
+		}
+	}
+}
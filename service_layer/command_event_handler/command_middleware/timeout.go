@@ -0,0 +1,83 @@
+package commandmiddleware
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// TimeoutConfig holds the deadlines applied to command handling
+type TimeoutConfig struct {
+	Default  time.Duration            // Deadline of commands without a specific one; zero means no deadline
+	Commands map[string]time.Duration // Deadlines keyed by command type name, e.g. "*commands.CreateOrder"
+}
+
+// DefaultTimeoutConfig returns default configuration for the timeout middleware
+func DefaultTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Default:  30 * time.Second,
+		Commands: make(map[string]time.Duration),
+	}
+}
+
+// Timeout creates a middleware that cancels the command context once the command's deadline
+// passes and returns a timeout error right away. The handler runs in its own goroutine and
+// is not stopped: handlers are expected to honour ctx (database and network calls do), and
+// whatever a late handler returns is discarded. A panic in the handler is re-raised in the
+// caller, so Recovery still sees it.
+func Timeout(cfg TimeoutConfig) Middleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) error {
+			cmdName := reflect.TypeOf(cmd).String()
+
+			timeout, ok := cfg.Commands[cmdName]
+			if !ok {
+				timeout = cfg.Default
+			}
+			if timeout <= 0 {
+				return next(ctx, cmd)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan error, 1)
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						panicked <- r
+					}
+				}()
+				done <- next(ctx, cmd)
+			}()
+
+			var err error
+			select {
+			case err = <-done:
+			case r := <-panicked:
+				panic(r)
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			// A handler finishing after the deadline is reported as timed out even when it
+			// succeeded, since the caller could not wait for it
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				logging.Warn("Command deadline exceeded").
+					WithAny("command_name", cmdName).
+					WithAny("timeout", timeout).
+					WithError(err).
+					Log()
+				return apperrors.Timeout(phrases.CommandTimeout, timeout.String())
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package commandmiddleware
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+)
+
+const tracerName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command"
+
+// Tracing creates a middleware that starts a child span for every command
+func Tracing() Middleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) error {
+			cmdName := reflect.TypeOf(cmd).String()
+
+			ctx, span := otel.Tracer(tracerName).Start(ctx, "command "+cmdName,
+				trace.WithSpanKind(trace.SpanKindInternal),
+				trace.WithAttributes(
+					attribute.String("messaging.command.name", cmdName),
+					attribute.String("messaging.correlation_id", adapter.CorrelationIDFromContext(ctx)),
+				),
+			)
+			defer span.End()
+
+			if err := next(ctx, cmd); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return err
+			}
+
+			span.SetStatus(codes.Ok, "")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package commandmiddleware
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+	"github.com/ali-mahdavi-dev/shikposh-framework/helpers/validation"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// Validation creates a middleware that validates commands before they are handled, using
+// their `validate` struct tags and their Validate() error method when implemented.
+// Invalid commands are rejected with a validation error listing the failing fields,
+// whether they were dispatched from HTTP, consumers or jobs.
+func Validation() Middleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, cmd any) error {
+			if err := validation.Validate(cmd, phrases.CommandValidationFailed); err != nil {
+				logging.Warn("Command validation failed").
+					WithAny("command_name", reflect.TypeOf(cmd).String()).
+					WithError(err).
+					Log()
+				return err
+			}
+
+			return next(ctx, cmd)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package commandeventhandler
+
+import (
+	"context"
+)
+
+// NewCommandHandlerWithResult creates a command handler that returns a result, such as the ID
+// of a created entity. It is a regular CommandHandler, so it runs through the command
+// middleware chain; the result is handed back to callers using messagebus.Dispatch.
+func NewCommandHandlerWithResult[Command any, Result any](
+	handleFunc func(ctx context.Context, cmd *Command) (Result, error),
+) CommandHandler {
+	return &genericCommandHandlerWithResult[Command, Result]{
+		handleFunc: handleFunc,
+		name:       funcName(handleFunc),
+	}
+}
+
+type genericCommandHandlerWithResult[Command any, Result any] struct {
+	handleFunc func(ctx context.Context, cmd *Command) (Result, error)
+	name       string
+}
+
+func (c genericCommandHandlerWithResult[Command, Result]) HandlerName() string {
+	return c.name
+}
+
+func (c genericCommandHandlerWithResult[Command, Result]) NewCommand() any {
+	tVar := new(Command)
+	return tVar
+}
+
+func (c genericCommandHandlerWithResult[Command, Result]) NewResult() any {
+	tVar := new(Result)
+	return tVar
+}
+
+func (c genericCommandHandlerWithResult[Command, Result]) Handle(ctx context.Context, cmd any) error {
+	command := cmd.(*Command)
+	result, err := c.handleFunc(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	SetCommandResult(ctx, command, result)
+	return nil
+}
+
+type commandResultKey struct{}
+
+// commandResult receives the result of one dispatched command
+type commandResult struct {
+	cmd   any
+	value any
+	set   bool
+}
+
+// ContextWithCommandResult returns a context that receives the result of handling cmd
+func ContextWithCommandResult(ctx context.Context, cmd any) context.Context {
+	return context.WithValue(ctx, commandResultKey{}, &commandResult{cmd: cmd})
+}
+
+// SetCommandResult stores the result of cmd in ctx. Results of other commands handled
+// with the same context (e.g. commands dispatched from within a handler) are ignored.
+func SetCommandResult(ctx context.Context, cmd any, value any) {
+	holder, ok := ctx.Value(commandResultKey{}).(*commandResult)
+	if !ok || holder.cmd != cmd {
+		return
+	}
+	holder.value = value
+	holder.set = true
+}
+
+// CommandResultFromContext returns the result stored by the handler of the dispatched command
+func CommandResultFromContext(ctx context.Context) (any, bool) {
+	holder, ok := ctx.Value(commandResultKey{}).(*commandResult)
+	if !ok || !holder.set {
+		return nil, false
+	}
+	return holder.value, true
+}
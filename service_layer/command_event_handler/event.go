@@ -15,11 +15,17 @@ func NewEventHandler[Event any](
 ) EventHandler {
 	return &genericEventHandler[Event]{
 		handleFunc: handleFunc,
+		name:       funcName(handleFunc),
 	}
 }
 
 type genericEventHandler[Event any] struct {
 	handleFunc func(ctx context.Context, cmd *Event) error
+	name       string
+}
+
+func (c genericEventHandler[Event]) HandlerName() string {
+	return c.name
 }
 
 func (c genericEventHandler[Event]) NewEvent() any {
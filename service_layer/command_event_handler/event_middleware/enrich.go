@@ -0,0 +1,13 @@
+package eventmiddleware
+
+import "context"
+
+// ContextEnricher creates a middleware that derives the handler context from the event,
+// e.g. to attach the tenant or locale carried by the event before the handler runs
+func ContextEnricher(enrich func(ctx context.Context, event any) context.Context) Middleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(ctx context.Context, event any) error {
+			return next(enrich(ctx, event), event)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package eventmiddleware
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+const meterName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/event"
+
+// Metrics creates a middleware that records the number, latency and errors of
+// event handler invocations, labelled by event and handler
+func Metrics() Middleware {
+	meter := otel.Meter(meterName)
+
+	handled, err := meter.Int64Counter("messaging.event.handled",
+		metric.WithDescription("Number of event handler invocations"))
+	if err != nil {
+		logging.Warn("Failed to create event counter").WithError(err).Log()
+		handled = noop.Int64Counter{}
+	}
+	failed, err := meter.Int64Counter("messaging.event.errors",
+		metric.WithDescription("Number of event handler invocations that returned an error"))
+	if err != nil {
+		logging.Warn("Failed to create event error counter").WithError(err).Log()
+		failed = noop.Int64Counter{}
+	}
+	duration, err := meter.Float64Histogram("messaging.event.duration",
+		metric.WithDescription("Duration of event handler invocations"),
+		metric.WithUnit("s"))
+	if err != nil {
+		logging.Warn("Failed to create event duration histogram").WithError(err).Log()
+		duration = noop.Float64Histogram{}
+	}
+
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(ctx context.Context, event any) error {
+			nameAttrs := []attribute.KeyValue{
+				attribute.String("messaging.event.name", eventschema.Name(event)),
+				attribute.String("messaging.handler.name", HandlerNameFromContext(ctx)),
+			}
+			start := time.Now()
+
+			err := next(ctx, event)
+
+			outcome := "success"
+			if err != nil {
+				outcome = "error"
+				errorType := string(apperrors.ErrorTypeInternal)
+				if appErr, ok := apperrors.As(err); ok {
+					errorType = string(appErr.Type())
+				}
+				failed.Add(ctx, 1, metric.WithAttributes(append(nameAttrs, attribute.String("error.type", errorType))...))
+			}
+			attrs := metric.WithAttributes(append(nameAttrs, attribute.String("outcome", outcome))...)
+			handled.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+
+			return err
+		}
+	}
+}
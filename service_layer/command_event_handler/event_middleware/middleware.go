@@ -0,0 +1,45 @@
+package eventmiddleware
+
+import "context"
+
+// EventHandlerFunc is the base function type for event handlers
+type EventHandlerFunc func(ctx context.Context, event any) error
+
+// Middleware is a function that wraps an EventHandlerFunc with additional behavior
+type Middleware func(next EventHandlerFunc) EventHandlerFunc
+
+// Chain chains multiple middlewares together, applying them in order
+// The first middleware in the slice will be the outermost layer
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		// Apply middlewares in reverse order so the first one is outermost
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// Apply applies a middleware to a handler function
+func Apply(handler EventHandlerFunc, middleware Middleware) EventHandlerFunc {
+	return middleware(handler)
+}
+
+// ApplyChain applies a chain of middlewares to a handler function
+func ApplyChain(handler EventHandlerFunc, middlewares ...Middleware) EventHandlerFunc {
+	return Chain(middlewares...)(handler)
+}
+
+type handlerNameKey struct{}
+
+// ContextWithHandlerName stores the name of the event handler being invoked.
+// The message bus sets it before running the middleware chain of every subscriber.
+func ContextWithHandlerName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, handlerNameKey{}, name)
+}
+
+// HandlerNameFromContext returns the name of the event handler being invoked
+func HandlerNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(handlerNameKey{}).(string)
+	return name
+}
@@ -0,0 +1,37 @@
+package eventmiddleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// Recovery creates a middleware that converts a panic in an event handler into an
+// internal error and logs it with its stack trace. The error then goes through the
+// bus' retry and dead-letter handling like any other failure.
+func Recovery() Middleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(ctx context.Context, event any) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					eventName := eventschema.Name(event)
+
+					logging.Error("Event handler panicked").
+						WithAny("event_name", eventName).
+						WithString("handler", HandlerNameFromContext(ctx)).
+						WithAny("panic", r).
+						WithString("stack", string(debug.Stack())).
+						Log()
+
+					err = apperrors.Internal(fmt.Sprintf("panic while handling %s: %v", eventName, r))
+				}
+			}()
+
+			return next(ctx, event)
+		}
+	}
+}
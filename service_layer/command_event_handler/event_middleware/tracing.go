@@ -0,0 +1,53 @@
+package eventmiddleware
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+const tracerName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/event"
+
+// Tracing creates a middleware that starts a child span for every event handler invocation.
+// Events handled asynchronously continue the trace of the unit of work that raised them.
+func Tracing() Middleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(ctx context.Context, event any) error {
+			eventName := eventschema.Name(event)
+
+			attributes := []attribute.KeyValue{
+				attribute.String("messaging.event.name", eventName),
+				attribute.String("messaging.handler.name", HandlerNameFromContext(ctx)),
+			}
+			if envelope, ok := adapter.EnvelopeFromContext(ctx); ok {
+				ctx = envelope.ContextWithTrace(ctx)
+				attributes = append(attributes,
+					attribute.String("messaging.message.id", envelope.EventID),
+					attribute.String("messaging.correlation_id", envelope.CorrelationID),
+					attribute.String("messaging.causation_id", envelope.CausationID),
+				)
+			}
+
+			ctx, span := otel.Tracer(tracerName).Start(ctx, "event "+eventName,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attributes...),
+			)
+			defer span.End()
+
+			if err := next(ctx, event); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return err
+			}
+
+			span.SetStatus(codes.Ok, "")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package commandeventhandler
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// NamedHandler is implemented by handlers that can report a name for logging and introspection
+type NamedHandler interface {
+	HandlerName() string
+}
+
+// HandlerName returns the name of a handler, falling back to its type name
+func HandlerName(handler any) string {
+	if named, ok := handler.(NamedHandler); ok {
+		return named.HandlerName()
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+// funcName returns the fully qualified name of a function, e.g. "orders.(*Service).SendEmail"
+func funcName(fn any) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(value.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := f.Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return strings.TrimSuffix(name, "-fm")
+}
@@ -0,0 +1,44 @@
+package commandeventhandler
+
+import (
+	"context"
+)
+
+type QueryHandler interface {
+	NewQuery() any
+	NewResult() any
+	Handle(ctx context.Context, query any) (any, error)
+}
+
+func NewQueryHandler[Query any, Result any](
+	handleFunc func(ctx context.Context, query *Query) (Result, error),
+) QueryHandler {
+	return &genericQueryHandler[Query, Result]{
+		handleFunc: handleFunc,
+		name:       funcName(handleFunc),
+	}
+}
+
+type genericQueryHandler[Query any, Result any] struct {
+	handleFunc func(ctx context.Context, query *Query) (Result, error)
+	name       string
+}
+
+func (c genericQueryHandler[Query, Result]) HandlerName() string {
+	return c.name
+}
+
+func (c genericQueryHandler[Query, Result]) NewQuery() any {
+	tVar := new(Query)
+	return tVar
+}
+
+func (c genericQueryHandler[Query, Result]) NewResult() any {
+	tVar := new(Result)
+	return tVar
+}
+
+func (c genericQueryHandler[Query, Result]) Handle(ctx context.Context, query any) (any, error) {
+	q := query.(*Query)
+	return c.handleFunc(ctx, q)
+}
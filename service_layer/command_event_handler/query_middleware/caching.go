@@ -0,0 +1,63 @@
+package querymiddleware
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/cache"
+)
+
+// CacheableQuery is implemented by queries whose results can be cached.
+// Queries that do not implement it bypass the cache.
+type CacheableQuery interface {
+	// CacheKey returns the key under which the result is cached
+	CacheKey() string
+	// CacheTTL returns how long the result stays in the cache
+	CacheTTL() time.Duration
+}
+
+// Caching creates a middleware that serves CacheableQuery results from the store
+// and caches successful results on a miss. Cache failures are logged and never fail the query.
+func Caching(store cache.Store) Middleware {
+	return func(next QueryHandlerFunc) QueryHandlerFunc {
+		return func(ctx context.Context, query any) (any, error) {
+			cacheable, ok := query.(CacheableQuery)
+			if !ok {
+				return next(ctx, query)
+			}
+
+			key := cacheable.CacheKey()
+			if target, ok := NewResult(ctx); ok {
+				err := store.GetValue(ctx, key, target)
+				if err == nil {
+					return reflect.ValueOf(target).Elem().Interface(), nil
+				}
+				if !errors.Is(err, redis.Nil) {
+					logging.Warn("Failed to read query result from cache").
+						WithString("cache_key", key).
+						WithError(err).
+						Log()
+				}
+			}
+
+			result, err := next(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := store.SetValue(ctx, key, result, cacheable.CacheTTL()); err != nil {
+				logging.Warn("Failed to cache query result").
+					WithString("cache_key", key).
+					WithError(err).
+					Log()
+			}
+
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package querymiddleware
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// Logging creates a middleware that logs query execution
+func Logging() Middleware {
+	return func(next QueryHandlerFunc) QueryHandlerFunc {
+		return func(ctx context.Context, query any) (any, error) {
+			queryName := reflect.TypeOf(query).String()
+			start := time.Now()
+
+			logging.Debug("Query received").
+				WithAny("query_name", queryName).
+				WithAny("query", query).
+				Log()
+
+			result, err := next(ctx, query)
+			if err != nil {
+				logging.Error("Query failed").
+					WithAny("query_name", queryName).
+					WithAny("query", query).
+					WithAny("latency", time.Since(start)).
+					WithError(err).
+					Log()
+				return nil, err
+			}
+
+			logging.Debug("Query handled successfully").
+				WithAny("query_name", queryName).
+				WithAny("latency", time.Since(start)).
+				Log()
+
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package querymiddleware
+
+import "context"
+
+// QueryHandlerFunc is the base function type for query handlers
+type QueryHandlerFunc func(ctx context.Context, query any) (any, error)
+
+// Middleware is a function that wraps a QueryHandlerFunc with additional behavior
+type Middleware func(next QueryHandlerFunc) QueryHandlerFunc
+
+// Chain chains multiple middlewares together, applying them in order
+// The first middleware in the slice will be the outermost layer
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next QueryHandlerFunc) QueryHandlerFunc {
+		// Apply middlewares in reverse order so the first one is outermost
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// Apply applies a middleware to a handler function
+func Apply(handler QueryHandlerFunc, middleware Middleware) QueryHandlerFunc {
+	return middleware(handler)
+}
+
+// ApplyChain applies a chain of middlewares to a handler function
+func ApplyChain(handler QueryHandlerFunc, middlewares ...Middleware) QueryHandlerFunc {
+	return Chain(middlewares...)(handler)
+}
+
+type resultFactoryKey struct{}
+
+// ContextWithResultFactory stores the factory of the handler's result type in the context.
+// The query bus sets it before running the middleware chain so middlewares such as
+// Caching can decode results without knowing their concrete type.
+func ContextWithResultFactory(ctx context.Context, factory func() any) context.Context {
+	return context.WithValue(ctx, resultFactoryKey{}, factory)
+}
+
+// NewResult returns a pointer to a new zero value of the handler's result type
+func NewResult(ctx context.Context) (any, bool) {
+	factory, ok := ctx.Value(resultFactoryKey{}).(func() any)
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
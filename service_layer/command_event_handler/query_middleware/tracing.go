@@ -0,0 +1,38 @@
+package querymiddleware
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/query"
+
+// Tracing creates a middleware that starts a child span for every query
+func Tracing() Middleware {
+	return func(next QueryHandlerFunc) QueryHandlerFunc {
+		return func(ctx context.Context, query any) (any, error) {
+			queryName := reflect.TypeOf(query).String()
+
+			ctx, span := otel.Tracer(tracerName).Start(ctx, "query "+queryName,
+				trace.WithSpanKind(trace.SpanKindInternal),
+				trace.WithAttributes(attribute.String("messaging.query.name", queryName)),
+			)
+			defer span.End()
+
+			result, err := next(ctx, query)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+
+			span.SetStatus(codes.Ok, "")
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package deadletter
+
+import (
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+)
+
+// DeadLetterStatus represents the status of a dead-lettered event
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusPending   DeadLetterStatus = "pending"
+	DeadLetterStatusReplayed  DeadLetterStatus = "replayed"
+	DeadLetterStatusDiscarded DeadLetterStatus = "discarded"
+)
+
+// DeadLetterID is the type for dead letter ID
+type DeadLetterID uint64
+
+// DeadLetter captures an event whose handler kept failing after all retry attempts
+type DeadLetter struct {
+	adapter.BaseEntity
+	ID            DeadLetterID `gorm:"primaryKey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	EventID       string           `json:"event_id" gorm:"event_id;index"`
+	CorrelationID string           `json:"correlation_id" gorm:"correlation_id;index"`
+	EventType     string           `json:"event_type" gorm:"event_type;index"`
+	EventVersion  int              `json:"event_version" gorm:"event_version;default:1"` // Schema version of the payload
+	HandlerName   string           `json:"handler_name" gorm:"handler_name"`
+	Payload       string           `json:"payload" gorm:"type:text"`
+	ErrorMessage  string           `json:"error_message" gorm:"error_message;type:text"`
+	Attempts      int              `json:"attempts" gorm:"attempts"`
+	Status        DeadLetterStatus `json:"status" gorm:"status;index;default:'pending'"`
+	ReplayCount   int              `json:"replay_count" gorm:"replay_count;default:0"`
+	ReplayedAt    *time.Time       `json:"replayed_at,omitempty" gorm:"replayed_at"`
+}
+
+// GetID returns the dead letter ID as uint64
+func (d *DeadLetter) GetID() uint64 {
+	return uint64(d.ID)
+}
+
+// TableName returns the table name for the dead letter
+func (d *DeadLetter) TableName() string {
+	return "dead_letter_events"
+}
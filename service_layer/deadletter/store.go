@@ -0,0 +1,121 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+)
+
+// ListFilter narrows the dead letters returned by Store.List
+type ListFilter struct {
+	EventType string           // Only dead letters of this event type, if set
+	Status    DeadLetterStatus // Only dead letters with this status, if set
+	Limit     int              // Maximum number of results (default 50)
+	Offset    int
+}
+
+// Store defines the interface for dead letter persistence
+type Store interface {
+	Save(ctx context.Context, deadLetter *DeadLetter) error
+	Get(ctx context.Context, id DeadLetterID) (*DeadLetter, error)
+	List(ctx context.Context, filter ListFilter) ([]*DeadLetter, error)
+	MarkReplayed(ctx context.Context, id DeadLetterID) error
+	MarkReplayFailed(ctx context.Context, id DeadLetterID, errorMsg string) error
+	MarkDiscarded(ctx context.Context, id DeadLetterID) error
+}
+
+// GormStore is a GORM implementation of the dead letter store
+type GormStore struct {
+	db        *gorm.DB
+	tableName string
+}
+
+// NewGormStore creates a new GORM-based dead letter store
+// If tableName is empty, it will use the default table name from DeadLetter.TableName()
+func NewGormStore(db *gorm.DB, tableName string) Store {
+	return &GormStore{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (s *GormStore) Model(ctx context.Context) *gorm.DB {
+	model := s.db.WithContext(ctx).Model(&DeadLetter{})
+	if s.tableName != "" {
+		model = model.Table(s.tableName)
+	}
+	return model
+}
+
+func (s *GormStore) Save(ctx context.Context, deadLetter *DeadLetter) error {
+	return s.Model(ctx).Create(deadLetter).Error
+}
+
+func (s *GormStore) Get(ctx context.Context, id DeadLetterID) (*DeadLetter, error) {
+	var deadLetter DeadLetter
+	err := s.Model(ctx).Where("id = ?", uint64(id)).First(&deadLetter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, adapter.ErrEntityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+func (s *GormStore) List(ctx context.Context, filter ListFilter) ([]*DeadLetter, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+
+	query := s.Model(ctx)
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var deadLetters []*DeadLetter
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&deadLetters).Error
+	return deadLetters, err
+}
+
+func (s *GormStore) MarkReplayed(ctx context.Context, id DeadLetterID) error {
+	now := time.Now()
+	return s.Model(ctx).
+		Where("id = ?", uint64(id)).
+		Updates(map[string]interface{}{
+			"status":       DeadLetterStatusReplayed,
+			"replay_count": gorm.Expr("replay_count + 1"),
+			"replayed_at":  now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (s *GormStore) MarkReplayFailed(ctx context.Context, id DeadLetterID, errorMsg string) error {
+	return s.Model(ctx).
+		Where("id = ?", uint64(id)).
+		Updates(map[string]interface{}{
+			"error_message": errorMsg,
+			"replay_count":  gorm.Expr("replay_count + 1"),
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+func (s *GormStore) MarkDiscarded(ctx context.Context, id DeadLetterID) error {
+	return s.Model(ctx).
+		Where("id = ?", uint64(id)).
+		Updates(map[string]interface{}{
+			"status":     DeadLetterStatusDiscarded,
+			"updated_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,60 @@
+package eventschema
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the registry used by the message bus, the outbox and the dead letter store
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register registers an event type in the default registry
+func Register(sample any, opts ...Option) error {
+	return defaultRegistry.Register(sample, opts...)
+}
+
+// MustRegister is like Register but panics on error; it is meant for init functions
+func MustRegister(sample any, opts ...Option) {
+	if err := defaultRegistry.Register(sample, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterUpcaster registers an upcaster in the default registry
+func RegisterUpcaster(name string, fromVersion int, fn Upcaster) error {
+	return defaultRegistry.RegisterUpcaster(name, fromVersion, fn)
+}
+
+// Name returns the name of event according to the default registry
+func Name(event any) string {
+	return defaultRegistry.Name(event)
+}
+
+// Version returns the schema version of event according to the default registry
+func Version(event any) int {
+	return defaultRegistry.Version(event)
+}
+
+// CurrentVersion returns the current version of the named event in the default registry
+func CurrentVersion(name string) int {
+	return defaultRegistry.CurrentVersion(name)
+}
+
+// Upcast upcasts a payload to the current version using the default registry
+func Upcast(name string, version int, payload map[string]any) (map[string]any, error) {
+	return defaultRegistry.Upcast(name, version, payload)
+}
+
+// DecodeInto upcasts and decodes a payload into target using the default registry
+func DecodeInto(name string, version int, data []byte, target any) error {
+	return defaultRegistry.DecodeInto(name, version, data, target)
+}
+
+// Decode upcasts and decodes a payload into its registered type using the default registry
+func Decode(name string, version int, data []byte) (any, error) {
+	return defaultRegistry.Decode(name, version, data)
+}
+
+// New returns a new value of the type registered under name in the default registry
+func New(name string) (any, bool) {
+	return defaultRegistry.New(name)
+}
@@ -0,0 +1,266 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// NamedEvent is implemented by events with an explicit, stable name. The name is used to
+// route the event and is stored with it in the outbox and the dead letter store, so it must
+// not change when the Go type is renamed or moved.
+type NamedEvent interface {
+	EventName() string
+}
+
+// VersionedEvent is implemented by events that declare the version of their payload schema.
+// Bump it on incompatible payload changes and register an upcaster from the previous version.
+type VersionedEvent interface {
+	EventVersion() int
+}
+
+// Upcaster transforms the JSON payload of an event from one schema version to the next
+type Upcaster func(payload map[string]any) (map[string]any, error)
+
+// Option configures the registration of an event type
+type Option func(*entry)
+
+// WithName registers the event type under name instead of the one returned by EventName
+func WithName(name string) Option {
+	return func(e *entry) {
+		e.name = name
+	}
+}
+
+// WithVersion sets the current schema version instead of the one returned by EventVersion
+func WithVersion(version int) Option {
+	return func(e *entry) {
+		e.version = version
+	}
+}
+
+type entry struct {
+	name      string
+	version   int
+	typ       reflect.Type
+	upcasters map[int]Upcaster // Keyed by the version they upcast from
+}
+
+// Registry maps stable event names to Go types, schema versions and upcasters
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]*entry
+	byType map[reflect.Type]*entry
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]*entry),
+		byType: make(map[reflect.Type]*entry),
+	}
+}
+
+// Register registers the type of sample, e.g. &OrderPlaced{}, under its stable name and
+// current version. Registering two types under one name, or one type under two names, fails.
+func (r *Registry) Register(sample any, opts ...Option) error {
+	t := baseType(reflect.TypeOf(sample))
+	e := &entry{
+		name:      nameFromInterface(t),
+		version:   versionFromInterface(t),
+		typ:       t,
+		upcasters: make(map[int]Upcaster),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.name == "" {
+		return fmt.Errorf("event type %s has no name: implement NamedEvent or use WithName", t)
+	}
+	if e.version < 1 {
+		return fmt.Errorf("event %s version must be at least 1", e.name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byName[e.name]; ok {
+		if existing.typ != t {
+			return fmt.Errorf("event name %s is already registered for %s", e.name, existing.typ)
+		}
+		if existing.version != e.version {
+			return fmt.Errorf("event %s is already registered with version %d", e.name, existing.version)
+		}
+		return nil
+	}
+	if existing, ok := r.byType[t]; ok {
+		return fmt.Errorf("event type %s is already registered as %s", t, existing.name)
+	}
+
+	r.byName[e.name] = e
+	r.byType[t] = e
+	return nil
+}
+
+// RegisterUpcaster registers fn to transform payloads of the named event from fromVersion
+// to fromVersion+1. The event must be registered first.
+func (r *Registry) RegisterUpcaster(name string, fromVersion int, fn Upcaster) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.byName[name]
+	if !ok {
+		return fmt.Errorf("event %s is not registered", name)
+	}
+	if fromVersion < 1 || fromVersion >= e.version {
+		return fmt.Errorf("event %s has no version %d to upcast from (current version is %d)", name, fromVersion, e.version)
+	}
+
+	e.upcasters[fromVersion] = fn
+	return nil
+}
+
+// Name returns the name event is routed and stored under: the name it declares through
+// NamedEvent, the name its type is registered under, or else its Go type name
+func (r *Registry) Name(event any) string {
+	if named, ok := event.(NamedEvent); ok {
+		return named.EventName()
+	}
+
+	r.mu.RLock()
+	e, ok := r.byType[baseType(reflect.TypeOf(event))]
+	r.mu.RUnlock()
+	if ok {
+		return e.name
+	}
+	return reflect.TypeOf(event).String()
+}
+
+// Version returns the schema version of event, 1 for events without one
+func (r *Registry) Version(event any) int {
+	if versioned, ok := event.(VersionedEvent); ok {
+		return versioned.EventVersion()
+	}
+
+	r.mu.RLock()
+	e, ok := r.byType[baseType(reflect.TypeOf(event))]
+	r.mu.RUnlock()
+	if ok {
+		return e.version
+	}
+	return 1
+}
+
+// CurrentVersion returns the current schema version of the named event, 1 when it is not registered
+func (r *Registry) CurrentVersion(name string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if e, ok := r.byName[name]; ok {
+		return e.version
+	}
+	return 1
+}
+
+// New returns a pointer to a new value of the type registered under name
+func (r *Registry) New(name string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.byName[name]
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(e.typ).Interface(), true
+}
+
+// Upcast transforms payload, stored with the given schema version, to the current version
+// of the named event. Payloads of unregistered events and current payloads are returned as-is.
+// Version 0 stands for payloads stored before versioning and is treated as version 1.
+func (r *Registry) Upcast(name string, version int, payload map[string]any) (map[string]any, error) {
+	r.mu.RLock()
+	e, ok := r.byName[name]
+	r.mu.RUnlock()
+	if !ok {
+		return payload, nil
+	}
+
+	if version < 1 {
+		version = 1
+	}
+	if version > e.version {
+		return nil, fmt.Errorf("event %s version %d is newer than the supported version %d", name, version, e.version)
+	}
+
+	for ; version < e.version; version++ {
+		upcaster, ok := e.upcasters[version]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for event %s from version %d", name, version)
+		}
+
+		var err error
+		if payload, err = upcaster(payload); err != nil {
+			return nil, fmt.Errorf("failed to upcast event %s from version %d: %w", name, version, err)
+		}
+	}
+
+	return payload, nil
+}
+
+// DecodeInto upcasts a JSON payload of the named event and decodes it into target
+func (r *Registry) DecodeInto(name string, version int, data []byte, target any) error {
+	if version >= r.CurrentVersion(name) {
+		return json.Unmarshal(data, target)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	payload, err := r.Upcast(name, version, payload)
+	if err != nil {
+		return err
+	}
+
+	upcasted, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(upcasted, target)
+}
+
+// Decode upcasts a JSON payload of the named event and decodes it into a new value of its
+// registered type
+func (r *Registry) Decode(name string, version int, data []byte) (any, error) {
+	event, ok := r.New(name)
+	if !ok {
+		return nil, fmt.Errorf("event %s is not registered", name)
+	}
+	if err := r.DecodeInto(name, version, data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func nameFromInterface(t reflect.Type) string {
+	if named, ok := reflect.New(t).Interface().(NamedEvent); ok {
+		return named.EventName()
+	}
+	return ""
+}
+
+func versionFromInterface(t reflect.Type) int {
+	if versioned, ok := reflect.New(t).Interface().(VersionedEvent); ok {
+		return versioned.EventVersion()
+	}
+	return 1
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"time"
+)
+
+// JobStatus represents the status of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// JobID identifies a job; it is returned to callers so they can poll the job status
+type JobID string
+
+// Job is a command enqueued for asynchronous execution
+type Job struct {
+	ID            JobID `gorm:"primaryKey;type:varchar(36)"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CommandType   string     `json:"command_type" gorm:"command_type;index"`
+	Payload       string     `json:"payload" gorm:"type:text"`
+	Principal     string     `json:"principal,omitempty" gorm:"type:text"` // Serialized principal the command is authorized as
+	CorrelationID string     `json:"correlation_id,omitempty" gorm:"correlation_id"`
+	Status        JobStatus  `json:"status" gorm:"status;index;default:'pending'"`
+	Attempts      int        `json:"attempts" gorm:"attempts;default:0"`
+	MaxAttempts   int        `json:"max_attempts" gorm:"max_attempts;default:3"`
+	RunAt         time.Time  `json:"run_at" gorm:"run_at;index"`
+	Result        string     `json:"result,omitempty" gorm:"type:text"` // JSON result of commands handled with a result
+	LastError     *string    `json:"last_error,omitempty" gorm:"last_error;type:text"`
+	StartedAt     *time.Time `json:"started_at,omitempty" gorm:"started_at"`
+	LeaseUntil    *time.Time `json:"lease_until,omitempty" gorm:"lease_until;index"` // When a running job is considered abandoned
+	FinishedAt    *time.Time `json:"finished_at,omitempty" gorm:"finished_at"`
+}
+
+// TableName returns the table name for the job
+func (j *Job) TableName() string {
+	return "jobs"
+}
+
+// Done reports whether the job reached a final status
+func (j *Job) Done() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
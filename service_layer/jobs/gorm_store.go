@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GormStore is a GORM implementation of the job store
+type GormStore struct {
+	db        *gorm.DB
+	tableName string
+}
+
+// NewGormStore creates a new GORM-based job store
+// If tableName is empty, it will use the default table name from Job.TableName()
+func NewGormStore(db *gorm.DB, tableName string) Store {
+	return &GormStore{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (s *GormStore) Model(ctx context.Context) *gorm.DB {
+	model := s.db.WithContext(ctx).Model(&Job{})
+	if s.tableName != "" {
+		model = model.Table(s.tableName)
+	}
+	return model
+}
+
+func (s *GormStore) Enqueue(ctx context.Context, job *Job) error {
+	return s.Model(ctx).Create(job).Error
+}
+
+// Claim selects due pending jobs and jobs whose lease expired, and claims each with a
+// conditional update, so several workers (or processes) polling the same table never run
+// a job twice at the same time
+func (s *GormStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*Job, error) {
+	now := time.Now()
+
+	// Abandoned jobs without attempts left are not run again
+	err := s.Model(ctx).
+		Where("status = ? AND lease_until < ? AND attempts >= max_attempts", JobStatusRunning, now).
+		Updates(map[string]interface{}{
+			"status":      JobStatusFailed,
+			"last_error":  errLeaseExpired,
+			"lease_until": nil,
+			"finished_at": now,
+			"updated_at":  now,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimable := "((status = ? AND run_at <= ?) OR (status = ? AND lease_until < ?))"
+	var candidates []*Job
+	err = s.Model(ctx).
+		Where(claimable, JobStatusPending, now, JobStatusRunning, now).
+		Order("run_at ASC").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*Job, 0, len(candidates))
+	for _, job := range candidates {
+		startedAt := time.Now()
+		leaseUntil := startedAt.Add(lease)
+		result := s.Model(ctx).
+			Where("id = ?", job.ID).
+			Where(claimable, JobStatusPending, now, JobStatusRunning, now).
+			Updates(map[string]interface{}{
+				"status":      JobStatusRunning,
+				"attempts":    gorm.Expr("attempts + 1"),
+				"started_at":  startedAt,
+				"lease_until": leaseUntil,
+				"updated_at":  startedAt,
+			})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue // claimed by another worker
+		}
+
+		job.Status = JobStatusRunning
+		job.Attempts++
+		job.StartedAt = &startedAt
+		job.LeaseUntil = &leaseUntil
+		claimed = append(claimed, job)
+	}
+
+	return claimed, nil
+}
+
+func (s *GormStore) ExtendLease(ctx context.Context, id JobID, until time.Time) error {
+	return s.Model(ctx).
+		Where("id = ? AND status = ?", id, JobStatusRunning).
+		Updates(map[string]interface{}{
+			"lease_until": until,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+func (s *GormStore) Get(ctx context.Context, id JobID) (*Job, error) {
+	var job Job
+	err := s.Model(ctx).Where("id = ?", id).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *GormStore) MarkSucceeded(ctx context.Context, id JobID, result string) error {
+	now := time.Now()
+	return s.Model(ctx).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      JobStatusSucceeded,
+			"result":      result,
+			"lease_until": nil,
+			"finished_at": now,
+			"updated_at":  now,
+		}).Error
+}
+
+func (s *GormStore) MarkFailed(ctx context.Context, id JobID, errorMsg string, retryAt *time.Time) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_error":  errorMsg,
+		"lease_until": nil,
+		"updated_at":  now,
+	}
+	if retryAt != nil {
+		updates["status"] = JobStatusPending
+		updates["run_at"] = *retryAt
+	} else {
+		updates["status"] = JobStatusFailed
+		updates["finished_at"] = now
+	}
+
+	return s.Model(ctx).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (s *GormStore) Requeue(ctx context.Context, id JobID, errorMsg string) error {
+	now := time.Now()
+	return s.Model(ctx).
+		Where("id = ? AND status = ?", id, JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      JobStatusPending,
+			"attempts":    gorm.Expr("CASE WHEN attempts > 0 THEN attempts - 1 ELSE 0 END"),
+			"run_at":      now,
+			"last_error":  errorMsg,
+			"lease_until": nil,
+			"updated_at":  now,
+		}).Error
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import "time"
+
+// EnqueueOptions controls how an enqueued command is scheduled
+type EnqueueOptions struct {
+	Delay       time.Duration // Run the job no earlier than this long after enqueueing
+	MaxAttempts int           // Attempts before the job is marked failed; 0 uses the default
+}
+
+// EnqueueOption configures EnqueueOptions
+type EnqueueOption func(*EnqueueOptions)
+
+// WithDelay delays the job by d
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(o *EnqueueOptions) {
+		o.Delay = d
+	}
+}
+
+// WithMaxAttempts limits the number of times the job is attempted
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(o *EnqueueOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// DefaultMaxAttempts is the number of attempts of jobs enqueued without WithMaxAttempts
+const DefaultMaxAttempts = 3
+
+// ApplyOptions returns the options resulting from opts
+func ApplyOptions(opts ...EnqueueOption) EnqueueOptions {
+	options := EnqueueOptions{MaxAttempts: DefaultMaxAttempts}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	return options
+}
@@ -0,0 +1,258 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/redisx"
+)
+
+// RedisStoreConfig holds configuration for the Redis job store
+type RedisStoreConfig struct {
+	Prefix    string        // Prefix of every key used by the store
+	ResultTTL time.Duration // How long finished jobs are kept for polling
+}
+
+// DefaultRedisStoreConfig returns default configuration for the Redis job store
+func DefaultRedisStoreConfig() RedisStoreConfig {
+	return RedisStoreConfig{
+		Prefix:    "jobs",
+		ResultTTL: 7 * 24 * time.Hour,
+	}
+}
+
+// RedisStore keeps jobs in Redis: each job as a JSON value, due jobs in a list, delayed or
+// retried jobs in a sorted set scored by their run time and claimed jobs in a sorted set
+// scored by the end of their lease. Jobs move between these atomically and leave the
+// processing set only once their outcome is saved, so a crash never loses a job.
+type RedisStore struct {
+	conn   redisx.QueueConnection
+	config RedisStoreConfig
+}
+
+// NewRedisStore creates a new Redis-based job store
+func NewRedisStore(conn redisx.QueueConnection, cfg RedisStoreConfig) Store {
+	defaults := DefaultRedisStoreConfig()
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaults.Prefix
+	}
+	if cfg.ResultTTL <= 0 {
+		cfg.ResultTTL = defaults.ResultTTL
+	}
+
+	return &RedisStore{conn: conn, config: cfg}
+}
+
+func (s *RedisStore) jobKey(id JobID) string { return s.config.Prefix + ":job:" + string(id) }
+func (s *RedisStore) readyKey() string       { return s.config.Prefix + ":ready" }
+func (s *RedisStore) scheduledKey() string   { return s.config.Prefix + ":scheduled" }
+func (s *RedisStore) processingKey() string  { return s.config.Prefix + ":processing" }
+
+func (s *RedisStore) Enqueue(ctx context.Context, job *Job) error {
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if job.Status == "" {
+		job.Status = JobStatusPending
+	}
+
+	if err := s.save(ctx, job, 0); err != nil {
+		return err
+	}
+	return s.schedule(ctx, job)
+}
+
+func (s *RedisStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*Job, error) {
+	now := time.Now()
+
+	// Move delayed jobs that became due to the ready list
+	if _, err := s.conn.MoveDue(ctx, s.scheduledKey(), s.readyKey(), now, int64(limit)); err != nil {
+		return nil, err
+	}
+
+	// Lease ready jobs and jobs whose lease expired. Until a job's new status is saved below
+	// it stays leased, and is claimed again once the lease expires if saving fails.
+	leaseUntil := now.Add(lease)
+	ids, err := s.conn.LeaseValues(ctx, s.readyKey(), s.processingKey(), now, leaseUntil, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := s.Get(ctx, JobID(id))
+		if errors.Is(err, ErrJobNotFound) {
+			// Expired or deleted
+			if err := s.release(ctx, JobID(id)); err != nil {
+				return claimed, err
+			}
+			continue
+		}
+		if err != nil {
+			return claimed, err
+		}
+
+		switch {
+		case job.Done():
+			// Finished, but the worker stopped before releasing it
+			if err := s.release(ctx, job.ID); err != nil {
+				return claimed, err
+			}
+			continue
+		case job.Status == JobStatusRunning && job.LeaseUntil != nil && job.LeaseUntil.After(now):
+			// Also queued by a retry, while a worker is running it
+			continue
+		case job.Status == JobStatusRunning && job.Attempts >= job.MaxAttempts:
+			// Abandoned without attempts left
+			if err := s.MarkFailed(ctx, job.ID, errLeaseExpired, nil); err != nil {
+				return claimed, err
+			}
+			continue
+		}
+
+		startedAt := time.Now()
+		job.Status = JobStatusRunning
+		job.Attempts++
+		job.StartedAt = &startedAt
+		job.LeaseUntil = &leaseUntil
+		job.UpdatedAt = startedAt
+		if err := s.save(ctx, job, 0); err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, job)
+	}
+
+	return claimed, nil
+}
+
+func (s *RedisStore) ExtendLease(ctx context.Context, id JobID, until time.Time) error {
+	leased, err := s.conn.ExtendLease(ctx, s.processingKey(), string(id), until)
+	if err != nil || !leased {
+		return err
+	}
+
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status != JobStatusRunning {
+		return nil
+	}
+	job.LeaseUntil = &until
+	job.UpdatedAt = time.Now()
+	return s.save(ctx, job, 0)
+}
+
+func (s *RedisStore) Get(ctx context.Context, id JobID) (*Job, error) {
+	value, err := s.conn.GetValue(ctx, s.jobKey(id))
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *RedisStore) MarkSucceeded(ctx context.Context, id JobID, result string) error {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job.Status = JobStatusSucceeded
+	job.Result = result
+	job.LeaseUntil = nil
+	job.FinishedAt = &now
+	job.UpdatedAt = now
+	if err := s.save(ctx, job, s.config.ResultTTL); err != nil {
+		return err
+	}
+	return s.release(ctx, id)
+}
+
+func (s *RedisStore) MarkFailed(ctx context.Context, id JobID, errorMsg string, retryAt *time.Time) error {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job.LastError = &errorMsg
+	job.LeaseUntil = nil
+	job.UpdatedAt = now
+
+	if retryAt == nil {
+		job.Status = JobStatusFailed
+		job.FinishedAt = &now
+		if err := s.save(ctx, job, s.config.ResultTTL); err != nil {
+			return err
+		}
+		return s.release(ctx, id)
+	}
+
+	job.Status = JobStatusPending
+	job.RunAt = *retryAt
+	if err := s.save(ctx, job, 0); err != nil {
+		return err
+	}
+	if err := s.schedule(ctx, job); err != nil {
+		return err
+	}
+	return s.release(ctx, id)
+}
+
+func (s *RedisStore) Requeue(ctx context.Context, id JobID, errorMsg string) error {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if job.Attempts > 0 {
+		job.Attempts--
+	}
+	job.Status = JobStatusPending
+	job.RunAt = now
+	job.LastError = &errorMsg
+	job.LeaseUntil = nil
+	job.UpdatedAt = now
+	if err := s.save(ctx, job, 0); err != nil {
+		return err
+	}
+	if err := s.schedule(ctx, job); err != nil {
+		return err
+	}
+	return s.release(ctx, id)
+}
+
+func (s *RedisStore) save(ctx context.Context, job *Job, exp time.Duration) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.conn.SetValue(ctx, s.jobKey(job.ID), string(value), exp)
+}
+
+// release removes a job from the processing set once its outcome is saved
+func (s *RedisStore) release(ctx context.Context, id JobID) error {
+	return s.conn.RemoveScheduled(ctx, s.processingKey(), string(id))
+}
+
+// schedule makes a pending job ready now, or delays it until its run time
+func (s *RedisStore) schedule(ctx context.Context, job *Job) error {
+	if job.RunAt.After(time.Now()) {
+		return s.conn.AddScheduled(ctx, s.scheduledKey(), string(job.ID), job.RunAt)
+	}
+	return s.conn.PushValues(ctx, s.readyKey(), []string{string(job.ID)}, 0)
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrJobNotFound is returned when a job does not exist
+var ErrJobNotFound = errors.New("job not found")
+
+// errLeaseExpired is recorded on jobs whose lease expired during their last attempt
+const errLeaseExpired = "job lease expired before the job finished"
+
+// Store defines the interface for durable job persistence
+type Store interface {
+	// Enqueue stores a new pending job
+	Enqueue(ctx context.Context, job *Job) error
+	// Claim atomically marks up to limit due pending jobs as running, leased for lease, and
+	// returns them. Running jobs whose lease expired, because their worker crashed or lost
+	// the store, are claimed again, or failed when they have no attempts left.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Job, error)
+	// ExtendLease keeps a running job leased until the given time
+	ExtendLease(ctx context.Context, id JobID, until time.Time) error
+	Get(ctx context.Context, id JobID) (*Job, error)
+	MarkSucceeded(ctx context.Context, id JobID, result string) error
+	// MarkFailed records a failed attempt. The job is retried at retryAt when it is not nil,
+	// otherwise it is marked failed for good.
+	MarkFailed(ctx context.Context, id JobID, errorMsg string, retryAt *time.Time) error
+	// Requeue returns a running job whose attempt was interrupted, e.g. by shutdown, to the
+	// queue without counting that attempt
+	Requeue(ctx context.Context, id JobID, errorMsg string) error
+}
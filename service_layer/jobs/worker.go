@@ -0,0 +1,298 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/retry"
+)
+
+// Handler executes a claimed job and returns its result, if any
+type Handler interface {
+	HandleJob(ctx context.Context, job *Job) (any, error)
+}
+
+// WorkerConfig holds configuration for the job worker
+type WorkerConfig struct {
+	Concurrency  int           // Maximum number of jobs handled at the same time
+	PollInterval time.Duration // How often the store is polled when the queue is empty
+	BatchSize    int           // Maximum number of jobs claimed per poll
+	RetryPolicy  retry.Policy  // Backoff and error classification of failed jobs; MaxAttempts comes from the job
+	// LeaseDuration is how long a claimed job is reserved for this worker. The lease is renewed
+	// while the job runs; once it expires (the worker crashed) the job is claimed again.
+	LeaseDuration time.Duration
+}
+
+// DefaultWorkerConfig returns default configuration for the job worker
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		Concurrency:  10,
+		PollInterval: time.Second,
+		BatchSize:    10,
+		RetryPolicy:  retry.DefaultPolicy(),
+
+		LeaseDuration: 5 * time.Minute,
+	}
+}
+
+// Worker claims jobs from a store and runs them with a handler, typically the message bus.
+// It implements service_host.Service.
+type Worker struct {
+	store   Store
+	handler Handler
+	config  WorkerConfig
+
+	sem        chan struct{}
+	wg         sync.WaitGroup
+	ctx        context.Context // Cancelled when shutdown starts, to stop claiming jobs
+	cancel     context.CancelFunc
+	jobCtx     context.Context // Cancelled when shutdown hits its deadline, to stop running jobs
+	cancelJobs context.CancelFunc
+	stopOnce   sync.Once
+	doneChan   chan struct{}
+}
+
+// NewWorker creates a new job worker
+func NewWorker(store Store, handler Handler, cfg WorkerConfig) *Worker {
+	defaults := DefaultWorkerConfig()
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaults.Concurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaults.PollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = defaults.LeaseDuration
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	return &Worker{
+		store:      store,
+		handler:    handler,
+		config:     cfg,
+		sem:        make(chan struct{}, cfg.Concurrency),
+		ctx:        ctx,
+		cancel:     cancel,
+		jobCtx:     jobCtx,
+		cancelJobs: cancelJobs,
+		doneChan:   make(chan struct{}),
+	}
+}
+
+// Start polls the store and handles jobs until Shutdown is called
+func (w *Worker) Start() error {
+	defer close(w.doneChan)
+
+	logging.Info("Job worker started").
+		WithInt("concurrency", w.config.Concurrency).
+		WithAny("poll_interval", w.config.PollInterval).
+		Log()
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep claiming while the queue is busy, wait for the next tick once it is drained
+		for w.poll() {
+		}
+
+		select {
+		case <-w.ctx.Done():
+			w.wg.Wait()
+			logging.Info("Job worker stopped").Log()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll claims as many jobs as there are free slots and reports whether a full batch was claimed
+func (w *Worker) poll() bool {
+	if w.ctx.Err() != nil {
+		return false
+	}
+
+	// Wait for at least one free slot so claimed jobs never sit in memory
+	select {
+	case w.sem <- struct{}{}:
+		<-w.sem
+	case <-w.ctx.Done():
+		return false
+	}
+
+	limit := min(w.config.BatchSize, cap(w.sem)-len(w.sem))
+	jobs, err := w.store.Claim(w.ctx, limit, w.config.LeaseDuration)
+	if err != nil {
+		if w.ctx.Err() == nil {
+			logging.Error("Failed to claim jobs").WithError(err).Log()
+		}
+		return false
+	}
+
+	for _, job := range jobs {
+		w.sem <- struct{}{}
+		w.wg.Add(1)
+		go func(job *Job) {
+			defer func() {
+				<-w.sem
+				w.wg.Done()
+			}()
+			w.run(job)
+		}(job)
+	}
+
+	return len(jobs) == limit
+}
+
+// run handles one job and records its outcome. Jobs in flight at shutdown are allowed to
+// finish until the shutdown deadline; jobs cancelled then are queued to run again, and the
+// interrupted attempt is not counted.
+func (w *Worker) run(job *Job) {
+	stopRenewing := w.renewLease(job)
+	result, err := w.handle(w.jobCtx, job)
+	stopRenewing()
+
+	// The outcome is recorded even when shutdown cancelled the job
+	ctx := context.Background()
+	if err == nil {
+		w.succeed(ctx, job, result)
+		return
+	}
+
+	// An attempt cut short by shutdown does not count against MaxAttempts
+	if w.jobCtx.Err() != nil {
+		logging.Warn("Job interrupted by shutdown").
+			WithString("job_id", string(job.ID)).
+			WithString("command_type", job.CommandType).
+			WithError(err).
+			Log()
+
+		if requeueErr := w.store.Requeue(ctx, job.ID, err.Error()); requeueErr != nil {
+			logging.Error("Failed to requeue interrupted job").
+				WithString("job_id", string(job.ID)).
+				WithError(requeueErr).
+				Log()
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if job.Attempts < job.MaxAttempts && w.config.RetryPolicy.ShouldRetry(err) {
+		at := time.Now().Add(w.config.RetryPolicy.Backoff(job.Attempts))
+		retryAt = &at
+	}
+
+	logging.Warn("Job failed").
+		WithString("job_id", string(job.ID)).
+		WithString("command_type", job.CommandType).
+		WithInt("attempt", job.Attempts).
+		WithInt("max_attempts", job.MaxAttempts).
+		WithBool("will_retry", retryAt != nil).
+		WithError(err).
+		Log()
+
+	if markErr := w.store.MarkFailed(ctx, job.ID, err.Error(), retryAt); markErr != nil {
+		logging.Error("Failed to record job failure").
+			WithString("job_id", string(job.ID)).
+			WithError(markErr).
+			Log()
+	}
+}
+
+// renewLease extends the lease of a running job periodically until the returned function is called
+func (w *Worker) renewLease(job *Job) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(w.config.LeaseDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				until := time.Now().Add(w.config.LeaseDuration)
+				if err := w.store.ExtendLease(context.Background(), job.ID, until); err != nil {
+					logging.Warn("Failed to extend job lease").
+						WithString("job_id", string(job.ID)).
+						WithError(err).
+						Log()
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+func (w *Worker) handle(ctx context.Context, job *Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return w.handler.HandleJob(ctx, job)
+}
+
+func (w *Worker) succeed(ctx context.Context, job *Job, result any) {
+	var encoded string
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			logging.Warn("Failed to encode job result").
+				WithString("job_id", string(job.ID)).
+				WithError(err).
+				Log()
+		} else {
+			encoded = string(data)
+		}
+	}
+
+	if err := w.store.MarkSucceeded(ctx, job.ID, encoded); err != nil {
+		logging.Error("Failed to record job success").
+			WithString("job_id", string(job.ID)).
+			WithError(err).
+			Log()
+		return
+	}
+
+	logging.Debug("Job succeeded").
+		WithString("job_id", string(job.ID)).
+		WithString("command_type", job.CommandType).
+		WithInt("attempt", job.Attempts).
+		Log()
+}
+
+// Shutdown stops claiming new jobs and waits for running jobs to finish. When ctx is done
+// first, the contexts of running jobs are cancelled and the jobs are queued to run again.
+func (w *Worker) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(w.cancel)
+
+	select {
+	case <-w.doneChan:
+		return nil
+	case <-ctx.Done():
+		w.cancelJobs()
+		return ctx.Err()
+	}
+}
+
+// Name returns the name of the service for logging purposes
+func (w *Worker) Name() string {
+	return "job-worker"
+}
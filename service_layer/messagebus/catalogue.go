@@ -0,0 +1,45 @@
+package messagebus
+
+import (
+	"sort"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/catalogue"
+	commandeventhandler "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// Catalogue returns a read-only description of the registered commands and events, their
+// handlers and payload schemas, sorted by name. Pass it to catalogue.AsyncAPI to publish the
+// service contracts.
+func (m *messageBus) Catalogue() catalogue.Catalogue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var c catalogue.Catalogue
+	for name, handler := range m.handledCommands {
+		c.Commands = append(c.Commands, catalogue.NewMessage(
+			catalogue.MessageKindCommand,
+			name.(string),
+			handler.NewCommand(),
+			commandeventhandler.HandlerName(handler),
+		))
+	}
+
+	for name, handlers := range m.handledEvent {
+		if len(handlers) == 0 {
+			continue
+		}
+		names := make([]string, len(handlers))
+		for i, handler := range handlers {
+			names[i] = commandeventhandler.HandlerName(handler)
+		}
+		event := handlers[0].NewEvent()
+		message := catalogue.NewMessage(catalogue.MessageKindEvent, name.(string), event, names...)
+		message.Version = eventschema.Version(event)
+		c.Events = append(c.Events, message)
+	}
+
+	sort.Slice(c.Commands, func(i, j int) bool { return c.Commands[i].Name < c.Commands[j].Name })
+	sort.Slice(c.Events, func(i, j int) bool { return c.Events[i].Name < c.Events[j].Name })
+	return c
+}
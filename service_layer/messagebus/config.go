@@ -0,0 +1,71 @@
+package messagebus
+
+import (
+	"runtime"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/deadletter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/jobs"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/retry"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/transport"
+)
+
+// EventErrorPolicy controls how the bus reacts when one of several handlers of an event fails
+type EventErrorPolicy string
+
+const (
+	// EventErrorPolicyContinue runs every handler, logs failures and reports success
+	EventErrorPolicyContinue EventErrorPolicy = "continue"
+	// EventErrorPolicyStop stops at the first failing handler and returns its error
+	EventErrorPolicyStop EventErrorPolicy = "stop"
+	// EventErrorPolicyCollect runs every handler and returns all failures joined together
+	EventErrorPolicyCollect EventErrorPolicy = "collect"
+)
+
+// Config holds configuration for the message bus
+type Config struct {
+	EventErrorPolicy EventErrorPolicy       // How failures of event handlers are reported
+	Workers          int                    // Number of goroutines handling events concurrently
+	QueueCapacity    int                    // Capacity of each worker's event queue
+	PartitionKey     func(event any) string // Key routing related events to the same worker
+
+	DefaultRetryPolicy retry.Policy            // Retry policy of event handlers without a specific one
+	RetryPolicies      map[string]retry.Policy // Retry policies keyed by event name (see EventName)
+	DeadLetterStore    deadletter.Store        // Stores events whose handler exhausted its retries; nil disables dead-lettering
+
+	JobStore jobs.Store // Durable queue of commands handled asynchronously (see Enqueue); nil disables Enqueue
+
+	Transport          transport.Transport // Carries the events of EventTopics between processes; nil keeps every event in process
+	Codec              transport.Codec     // Serializes events for the transport; nil uses JSON
+	EventTopics        map[string]string   // Topics of the events published through the transport, keyed by event name
+	PublishRetryPolicy retry.Policy        // Retry policy of publishing to the transport
+}
+
+// eventTopic returns the topic the named event is published to, if it goes through the transport
+func (c Config) eventTopic(eventName string) (string, bool) {
+	if c.Transport == nil {
+		return "", false
+	}
+	topic, ok := c.EventTopics[eventName]
+	return topic, ok
+}
+
+// retryPolicy returns the retry policy for the given event name
+func (c Config) retryPolicy(eventName string) retry.Policy {
+	if policy, ok := c.RetryPolicies[eventName]; ok {
+		return policy
+	}
+	return c.DefaultRetryPolicy
+}
+
+// DefaultConfig returns default configuration for the message bus
+func DefaultConfig() Config {
+	return Config{
+		EventErrorPolicy: EventErrorPolicyCollect,
+		Workers:          runtime.NumCPU(),
+		QueueCapacity:    100,
+		PartitionKey:     DefaultPartitionKey,
+
+		DefaultRetryPolicy: retry.NoRetry(),
+		PublishRetryPolicy: retry.DefaultPolicy(),
+	}
+}
@@ -0,0 +1,153 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	commandeventhandler "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler"
+	eventmiddleware "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler/event_middleware"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/deadletter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// deadLetter stores an event whose handler exhausted its retries, or that was dropped by
+// shutdown, and reports whether it was stored. Failures to store the dead letter are logged
+// since the handler error is already being reported.
+func (m *messageBus) deadLetter(ctx context.Context, eventName, handlerName string, event any, attempts int, handlerErr error) bool {
+	if m.config.DeadLetterStore == nil {
+		return false
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logging.Error("Failed to marshal dead-lettered event").
+			WithAny("event_name", eventName).
+			WithString("handler", handlerName).
+			WithError(err).
+			Log()
+		return false
+	}
+
+	deadLetter := &deadletter.DeadLetter{
+		EventType:    eventName,
+		EventVersion: eventschema.Version(event),
+		HandlerName:  handlerName,
+		Payload:      string(payload),
+		ErrorMessage: handlerErr.Error(),
+		Attempts:     attempts,
+		Status:       deadletter.DeadLetterStatusPending,
+	}
+	if envelope, ok := adapter.EnvelopeFromContext(ctx); ok {
+		deadLetter.EventID = envelope.EventID
+		deadLetter.CorrelationID = envelope.CorrelationID
+	}
+
+	// The dead letter must be stored even when the handler failed because ctx was cancelled
+	if err := m.config.DeadLetterStore.Save(context.WithoutCancel(ctx), deadLetter); err != nil {
+		logging.Error("Failed to store dead-lettered event").
+			WithAny("event_name", eventName).
+			WithString("handler", handlerName).
+			WithError(err).
+			Log()
+		return false
+	}
+
+	logging.Warn("Event dead-lettered").
+		WithAny("event_name", eventName).
+		WithString("handler", handlerName).
+		WithUint("dead_letter_id", uint(deadLetter.ID)).
+		WithInt("attempts", attempts).
+		Log()
+	return true
+}
+
+// ReplayDeadLetter decodes a dead-lettered event and runs it once more through the handler
+// that failed it (or every handler of its event type if that handler is no longer registered
+// under the same name). Events that could not be published to the transport are published
+// again. The dead letter is marked replayed on success.
+func (m *messageBus) ReplayDeadLetter(ctx context.Context, id deadletter.DeadLetterID) error {
+	store := m.config.DeadLetterStore
+	if store == nil {
+		return errors.New("dead letter store is not configured")
+	}
+
+	deadLetter, err := store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter.Status != deadletter.DeadLetterStatusPending {
+		return apperrors.Conflict("", fmt.Sprintf("dead letter %d is %s", id, deadLetter.Status))
+	}
+
+	// Events that could not be published are published again instead of handled here
+	if topic, ok := m.config.eventTopic(deadLetter.EventType); ok && deadLetter.HandlerName == publisherName(topic) {
+		return m.replayPublish(ctx, deadLetter, topic)
+	}
+
+	m.mu.RLock()
+	handlers := m.handledEvent[deadLetter.EventType]
+	middlewares := m.eventMiddlewares
+	m.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return apperrors.NotFound("", fmt.Sprintf("event handler for %s not found", deadLetter.EventType))
+	}
+
+	targets := handlers
+	for _, h := range handlers {
+		if commandeventhandler.HandlerName(h) == deadLetter.HandlerName {
+			targets = []commandeventhandler.EventHandler{h}
+			break
+		}
+	}
+
+	logging.Info("Replaying dead-lettered event").
+		WithUint("dead_letter_id", uint(id)).
+		WithString("event_name", deadLetter.EventType).
+		WithString("handler", deadLetter.HandlerName).
+		Log()
+
+	// Replayed handlers keep the correlation chain of the original event
+	if deadLetter.CorrelationID != "" {
+		ctx = adapter.ContextWithCorrelationID(ctx, deadLetter.CorrelationID)
+	}
+	if deadLetter.EventID != "" {
+		ctx = adapter.ContextWithCausationID(ctx, deadLetter.EventID)
+	}
+
+	var errs []error
+	for _, h := range targets {
+		// Events dead-lettered before their schema changed are upcast to the current version
+		event := h.NewEvent()
+		if err := eventschema.DecodeInto(deadLetter.EventType, deadLetter.EventVersion, []byte(deadLetter.Payload), event); err != nil {
+			return fmt.Errorf("failed to decode dead letter %d: %w", id, err)
+		}
+
+		handlerName := commandeventhandler.HandlerName(h)
+		baseHandler := func(ctx context.Context, event any) error {
+			return h.Handle(ctx, event)
+		}
+		finalHandler := eventmiddleware.ApplyChain(baseHandler, middlewares...)
+		if err := finalHandler(eventmiddleware.ContextWithHandlerName(ctx, handlerName), event); err != nil {
+			errs = append(errs, fmt.Errorf("event handler %s for %s failed: %w", handlerName, deadLetter.EventType, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		logging.Error("Dead letter replay failed").
+			WithUint("dead_letter_id", uint(id)).
+			WithError(err).
+			Log()
+		if markErr := store.MarkReplayFailed(ctx, id, err.Error()); markErr != nil {
+			return errors.Join(err, markErr)
+		}
+		return err
+	}
+
+	return store.MarkReplayed(ctx, id)
+}
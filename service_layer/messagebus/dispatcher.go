@@ -0,0 +1,172 @@
+package messagebus
+
+import (
+	"context"
+	"hash/fnv"
+	"runtime/debug"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// PartitionedEvent is implemented by events that must be handled in order relative to
+// other events with the same key, typically the ID of the aggregate that emitted them
+type PartitionedEvent interface {
+	PartitionKey() string
+}
+
+// DefaultPartitionKey returns the key of events implementing PartitionedEvent.
+// Events without a key are partitioned by the aggregate in their envelope, or spread
+// across workers and handled in any order when that is unknown too.
+func DefaultPartitionKey(event any) string {
+	if partitioned, ok := event.(PartitionedEvent); ok {
+		return partitioned.PartitionKey()
+	}
+	return ""
+}
+
+// startWorkers starts the dispatcher that routes events from eventCh to a fixed pool of workers
+func (m *messageBus) startWorkers() {
+	m.workerChs = make([]chan adapter.EventWithWaitGroup, m.config.Workers)
+	for i := range m.workerChs {
+		m.workerChs[i] = make(chan adapter.EventWithWaitGroup, m.config.QueueCapacity)
+
+		m.wg.Add(1)
+		go m.runWorker(m.workerChs[i])
+	}
+
+	m.wg.Add(1)
+	go m.dispatch()
+}
+
+// dispatch routes every event to the worker owning its partition key. When eventCh is
+// closed or shutdown ends, worker queues are closed so workers exit after draining them,
+// and events sent afterwards are dropped so their senders are not blocked forever.
+func (m *messageBus) dispatch() {
+	defer m.wg.Done()
+
+	m.routeEvents()
+	for _, ch := range m.workerChs {
+		close(ch)
+	}
+
+	for eventWrapper := range m.eventCh {
+		m.dropEvent(eventWrapper)
+	}
+}
+
+func (m *messageBus) routeEvents() {
+	for {
+		select {
+		case <-m.stoppedCh:
+			return
+		case eventWrapper, ok := <-m.eventCh:
+			if !ok {
+				return
+			}
+			if !m.acceptEvent(eventWrapper) {
+				m.dropEvent(eventWrapper)
+				continue
+			}
+
+			select {
+			case m.workerChs[m.partition(eventWrapper)] <- eventWrapper:
+			case <-m.stoppedCh:
+				m.dropEvent(eventWrapper)
+				m.inflight.Done()
+				return
+			}
+		}
+	}
+}
+
+// partition picks a worker by hashing the partition key, or round-robin for events without one
+func (m *messageBus) partition(eventWrapper adapter.EventWithWaitGroup) int {
+	workers := uint64(len(m.workerChs))
+
+	key := m.partitionKey(eventWrapper.Event, eventWrapper.Envelope)
+	if key == "" {
+		return int(m.nextWorker.Add(1) % workers)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum64() % workers)
+}
+
+// partitionKey returns the key of events that must be handled in order: the configured
+// partition key, or the aggregate of the envelope. It is empty when neither is known.
+func (m *messageBus) partitionKey(event any, envelope adapter.EventEnvelope) string {
+	key := m.config.PartitionKey(event)
+	if key == "" && envelope.AggregateID != "" {
+		key = envelope.AggregateType + ":" + envelope.AggregateID
+	}
+	return key
+}
+
+func (m *messageBus) runWorker(ch <-chan adapter.EventWithWaitGroup) {
+	defer m.wg.Done()
+
+	for eventWrapper := range ch {
+		if m.stopped() {
+			m.dropEvent(eventWrapper)
+			m.inflight.Done()
+			continue
+		}
+
+		eventCtx := eventWrapper.Ctx
+		if eventCtx == nil {
+			eventCtx = context.Background()
+		}
+		eventCtx = context.WithValue(envelopeContext(eventCtx, eventWrapper), inflightKey{}, true)
+		m.handleQueuedEvent(eventCtx, eventWrapper)
+		m.inflight.Done()
+	}
+}
+
+// handleQueuedEvent handles one event from a worker queue. A panic escaping the handlers
+// is logged instead of killing the worker, and the sender is always released.
+func (m *messageBus) handleQueuedEvent(ctx context.Context, eventWrapper adapter.EventWithWaitGroup) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error("Event handling panicked").
+				WithAny("event_name", EventName(eventWrapper.Event)).
+				WithAny("panic", r).
+				WithString("stack", string(debug.Stack())).
+				Log()
+		}
+		// Signal that event is done being handled
+		if eventWrapper.Wg != nil {
+			eventWrapper.Wg.Done()
+		}
+	}()
+
+	// Events routed to a topic are published instead, and handled by its subscribers
+	if topic, ok := m.config.eventTopic(EventName(eventWrapper.Event)); ok {
+		if err := m.publish(ctx, topic, eventWrapper.Event); err != nil {
+			logging.Error("Failed to publish event").WithError(err).Log()
+			if eventWrapper.Reject != nil {
+				eventWrapper.Reject(err)
+			}
+		}
+		return
+	}
+
+	if err := m.HandleEvent(ctx, eventWrapper.Event); err != nil {
+		logging.Error("Failed to handle event").WithError(err).Log()
+	}
+}
+
+// envelopeContext stores the event envelope in ctx, joins the trace of the unit of work that
+// raised the event and makes the event the cause of anything its handlers do. Events sent without an envelope (not through the unit of work) get one here.
+func envelopeContext(ctx context.Context, eventWrapper adapter.EventWithWaitGroup) context.Context {
+	envelope := eventWrapper.Envelope
+	if envelope.EventID == "" {
+		envelope = adapter.NewEventEnvelope(ctx, eventWrapper.Event)
+	}
+
+	ctx = envelope.ContextWithTrace(ctx)
+	ctx = adapter.ContextWithEnvelope(ctx, envelope)
+	ctx = adapter.ContextWithCorrelationID(ctx, envelope.CorrelationID)
+	return adapter.ContextWithCausationID(ctx, envelope.EventID)
+}
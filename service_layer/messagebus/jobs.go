@@ -0,0 +1,130 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/authorization"
+	commandeventhandler "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/jobs"
+)
+
+// Enqueue stores cmd in the job store so a jobs.Worker handles it asynchronously, and returns
+// the job ID callers can poll with GetJob. The principal and correlation ID of ctx are kept
+// with the job, so the command is authorized and traced as if it was handled right away.
+func (m *messageBus) Enqueue(ctx context.Context, cmd any, opts ...jobs.EnqueueOption) (jobs.JobID, error) {
+	store := m.config.JobStore
+	if store == nil {
+		return "", errors.New("job store is not configured")
+	}
+
+	cmdName := reflect.TypeOf(cmd).String()
+	if _, ok := m.commandHandler(cmdName); !ok {
+		return "", fmt.Errorf("command handler for %s not found", cmdName)
+	}
+
+	_, done, err := m.acceptWork(ctx)
+	if err != nil {
+		return "", err
+	}
+	done()
+
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode command %s: %w", cmdName, err)
+	}
+
+	var principal string
+	if p, ok := authorization.PrincipalFromContext(ctx); ok {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode principal: %w", err)
+		}
+		principal = string(data)
+	}
+
+	options := jobs.ApplyOptions(opts...)
+	job := &jobs.Job{
+		ID:            jobs.JobID(uuid.New().String()),
+		CommandType:   cmdName,
+		Payload:       string(payload),
+		Principal:     principal,
+		CorrelationID: adapter.CorrelationIDFromContext(ctx),
+		Status:        jobs.JobStatusPending,
+		MaxAttempts:   options.MaxAttempts,
+		RunAt:         time.Now().Add(options.Delay),
+	}
+
+	if err := store.Enqueue(ctx, job); err != nil {
+		logging.Error("Failed to enqueue command").
+			WithAny("command_name", cmdName).
+			WithError(err).
+			Log()
+		return "", err
+	}
+
+	logging.Info("Command enqueued").
+		WithAny("command_name", cmdName).
+		WithString("job_id", string(job.ID)).
+		WithAny("run_at", job.RunAt).
+		Log()
+
+	return job.ID, nil
+}
+
+// HandleJob decodes the command of a claimed job and handles it through the command
+// middleware chain. It returns the result set by handlers created with
+// NewCommandHandlerWithResult, so the message bus can be used as a jobs.Handler.
+func (m *messageBus) HandleJob(ctx context.Context, job *jobs.Job) (any, error) {
+	handler, ok := m.commandHandler(job.CommandType)
+	if !ok {
+		return nil, apperrors.NotFound("", fmt.Sprintf("command handler for %s not found", job.CommandType))
+	}
+
+	cmd := handler.NewCommand()
+	if err := json.Unmarshal([]byte(job.Payload), cmd); err != nil {
+		return nil, apperrors.Validation("", fmt.Sprintf("failed to decode command %s: %v", job.CommandType, err))
+	}
+
+	if job.Principal != "" {
+		var principal authorization.Principal
+		if err := json.Unmarshal([]byte(job.Principal), &principal); err != nil {
+			return nil, apperrors.Validation("", fmt.Sprintf("failed to decode principal: %v", err))
+		}
+		ctx = authorization.ContextWithPrincipal(ctx, &principal)
+	}
+	if job.CorrelationID != "" {
+		ctx = adapter.ContextWithCorrelationID(ctx, job.CorrelationID)
+	}
+
+	ctx = commandeventhandler.ContextWithCommandResult(ctx, cmd)
+	if err := m.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	result, _ := commandeventhandler.CommandResultFromContext(ctx)
+	return result, nil
+}
+
+// GetJob returns the job with the given ID so callers can poll its status and result
+func (m *messageBus) GetJob(ctx context.Context, id jobs.JobID) (*jobs.Job, error) {
+	store := m.config.JobStore
+	if store == nil {
+		return nil, errors.New("job store is not configured")
+	}
+
+	job, err := store.Get(ctx, id)
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		return nil, apperrors.NotFound("", fmt.Sprintf("job %s not found", id))
+	}
+	return job, err
+}
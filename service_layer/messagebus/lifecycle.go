@@ -0,0 +1,206 @@
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// ErrShutdown is returned for commands and events sent after the message bus started shutting down
+var ErrShutdown = errors.New("message bus is shut down")
+
+// ShutdownError reports the events that were not handled because shutdown hit its deadline
+type ShutdownError struct {
+	Dropped   int64 // Events not handled
+	Persisted int64 // Dropped events stored in the dead letter store for replay
+	Err       error // Why draining stopped, typically context.DeadlineExceeded
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("message bus shutdown dropped %d events (%d persisted): %v", e.Dropped, e.Persisted, e.Err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+type inflightKey struct{}
+
+// isInflight reports whether ctx belongs to a command or event the bus already accepted.
+// Work started from it (commands dispatched by handlers, events raised by those commands)
+// is part of draining and is accepted after shutdown started.
+func isInflight(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	inflight, _ := ctx.Value(inflightKey{}).(bool)
+	return inflight
+}
+
+// acceptWork registers a command, or an event handled synchronously, as in flight. It returns
+// a function to call once it is handled, or ErrShutdown when the bus no longer accepts new work.
+func (m *messageBus) acceptWork(ctx context.Context) (context.Context, func(), error) {
+	if isInflight(ctx) {
+		return ctx, func() {}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return ctx, nil, ErrShutdown
+	}
+	m.inflight.Add(1)
+	return context.WithValue(ctx, inflightKey{}, true), m.inflight.Done, nil
+}
+
+// acceptEvent registers an event received on the event channel as in flight. Events that
+// are not part of in-flight work are rejected once shutdown started.
+func (m *messageBus) acceptEvent(eventWrapper adapter.EventWithWaitGroup) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed && !isInflight(eventWrapper.Ctx) {
+		return false
+	}
+	m.inflight.Add(1)
+	return true
+}
+
+// stopped reports whether draining ended, after which queued events are dropped
+func (m *messageBus) stopped() bool {
+	select {
+	case <-m.stoppedCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// dropEvent releases the sender of an event that will not be handled, telling it why, and
+// stores the event in the dead letter store (when configured) so it can be replayed
+func (m *messageBus) dropEvent(eventWrapper adapter.EventWithWaitGroup) {
+	ctx := eventWrapper.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = envelopeContext(ctx, eventWrapper)
+	eventName := EventName(eventWrapper.Event)
+
+	// Events routed to a topic were due to be published, so replaying them publishes them
+	handlerName := ""
+	if topic, ok := m.config.eventTopic(eventName); ok {
+		handlerName = publisherName(topic)
+	}
+
+	persisted := m.deadLetter(ctx, eventName, handlerName, eventWrapper.Event, 0, ErrShutdown)
+	m.dropped.Add(1)
+	if persisted {
+		m.persisted.Add(1)
+	}
+
+	withEnvelope(ctx, logging.Warn("Event dropped by message bus shutdown")).
+		WithAny("event_name", eventName).
+		WithBool("persisted", persisted).
+		Log()
+
+	if eventWrapper.Reject != nil {
+		eventWrapper.Reject(ErrShutdown)
+	}
+	if eventWrapper.Wg != nil {
+		eventWrapper.Wg.Done()
+	}
+}
+
+// Start subscribes to the transport topics of the events this bus has handlers for and blocks
+// until the message bus is shut down, so it can run as a service_host.Service.
+// The bus handles commands and events from the moment it is created.
+func (m *messageBus) Start() error {
+	m.subscribe()
+	<-m.doneCh
+	return nil
+}
+
+// Name returns the name of the service for logging purposes
+func (m *messageBus) Name() string {
+	return "message-bus"
+}
+
+// Shutdown gracefully shuts down the message bus. Transport subscriptions stop, new commands
+// are rejected with ErrShutdown and new events are dropped right away. Commands and events already accepted, including the
+// work they cause, are handled until ctx is done; events still queued then are dropped,
+// stored in the dead letter store when one is configured and reported in a *ShutdownError.
+func (m *messageBus) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		select {
+		case <-m.doneCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	m.closed = true
+	m.stopSubscriptions()
+	m.mu.Unlock()
+
+	logging.Info("Message bus shutting down").Log()
+
+	drained := make(chan struct{})
+	go func() {
+		m.subscriptions.Wait()
+		m.inflight.Wait()
+		close(drained)
+	}()
+
+	var drainErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		drainErr = ctx.Err()
+	}
+
+	// Workers drop whatever is still queued; the dispatcher rejects everything from now on
+	close(m.stoppedCh)
+	for _, ch := range m.workerChs {
+		m.dropQueued(ch)
+	}
+	close(m.doneCh)
+
+	dropped, persisted := m.dropped.Load(), m.persisted.Load()
+	if drainErr == nil && dropped == 0 {
+		logging.Info("Message bus shutdown completed successfully").Log()
+		return nil
+	}
+
+	logging.Warn("Message bus shutdown dropped events").
+		WithInt64("dropped", dropped).
+		WithInt64("persisted", persisted).
+		WithError(drainErr).
+		Log()
+	if drainErr == nil {
+		// Every accepted event was handled; the dropped ones were sent after shutdown started
+		return nil
+	}
+	return &ShutdownError{Dropped: dropped, Persisted: persisted, Err: drainErr}
+}
+
+// dropQueued drops the events waiting in a worker queue without blocking
+func (m *messageBus) dropQueued(ch chan adapter.EventWithWaitGroup) {
+	for {
+		select {
+		case eventWrapper, ok := <-ch:
+			if !ok {
+				return
+			}
+			m.dropEvent(eventWrapper)
+			m.inflight.Done()
+		default:
+			return
+		}
+	}
+}
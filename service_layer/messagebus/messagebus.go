@@ -2,15 +2,27 @@ package messagebus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
 	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
 	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/catalogue"
 	commandeventhandler "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler"
 	commandmiddleware "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler/command_middleware"
+	eventmiddleware "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler/event_middleware"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/deadletter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/jobs"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/retry"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/transport"
 )
 
 type MessageBus interface {
@@ -23,62 +35,141 @@ type MessageBus interface {
 	EventChannel() chan<- adapter.EventWithWaitGroup
 }
 
+// EventBus is implemented by buses that handle events directly and run event middleware
+type EventBus interface {
+	AddEventMiddleware(middlewares ...eventmiddleware.Middleware) error
+	HandleEvent(ctx context.Context, event any) error
+}
+
+// JobEnqueuer is implemented by buses that handle commands asynchronously through a job store
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, cmd any, opts ...jobs.EnqueueOption) (jobs.JobID, error)
+	GetJob(ctx context.Context, id jobs.JobID) (*jobs.Job, error)
+}
+
+// DeadLetterReplayer is implemented by buses that replay dead-lettered events
+type DeadLetterReplayer interface {
+	ReplayDeadLetter(ctx context.Context, id deadletter.DeadLetterID) error
+}
+
+// CatalogueProvider is implemented by buses that describe the messages they handle
+type CatalogueProvider interface {
+	Catalogue() catalogue.Catalogue
+}
+
+// Bus is the message bus created by NewMessageBus: MessageBus with every optional
+// capability, a jobs.Handler for job workers and a service_host service. Code that only
+// needs some capabilities should depend on MessageBus and the optional interfaces, so other
+// implementations and mocks need not provide the rest.
+type Bus interface {
+	MessageBus
+	EventBus
+	JobEnqueuer
+	DeadLetterReplayer
+	CatalogueProvider
+	jobs.Handler
+	Start() error
+	Name() string
+}
+
 type messageBus struct {
 	handledCommands    map[any]commandeventhandler.CommandHandler
-	handledEvent       map[any]commandeventhandler.EventHandler
+	handledEvent       map[any][]commandeventhandler.EventHandler
+	eventTypes         map[string]reflect.Type
 	commandMiddlewares []commandmiddleware.Middleware
+	eventMiddlewares   []eventmiddleware.Middleware
+	config             Config
 	uow                adapter.UnitOfWork
 	eventCh            chan adapter.EventWithWaitGroup
-	shutdownCh         chan struct{}
+	workerChs          []chan adapter.EventWithWaitGroup
+	nextWorker         atomic.Uint64
 	wg                 sync.WaitGroup
 	mu                 sync.RWMutex
-	closed             bool
+
+	// Lifecycle (see lifecycle.go)
+	closed    bool           // No new commands or events are accepted
+	inflight  sync.WaitGroup // Accepted commands and events not yet handled
+	stoppedCh chan struct{}  // Closed when draining ended; events not yet handled are dropped
+	doneCh    chan struct{}  // Closed when shutdown completed
+	dropped   atomic.Int64   // Events dropped since shutdown started
+	persisted atomic.Int64   // Dropped events stored in the dead letter store
+
+	// Transport subscriptions (see transport.go)
+	unsubscribe   context.CancelFunc // Stops the subscriptions
+	subscriptions sync.WaitGroup     // Running subscriptions
+}
+
+func NewMessageBus(uow adapter.UnitOfWork, eventCh chan adapter.EventWithWaitGroup) Bus {
+	return NewMessageBusWithConfig(uow, eventCh, DefaultConfig())
 }
 
-func NewMessageBus(uow adapter.UnitOfWork, eventCh chan adapter.EventWithWaitGroup) MessageBus {
+func NewMessageBusWithConfig(uow adapter.UnitOfWork, eventCh chan adapter.EventWithWaitGroup, cfg Config) Bus {
+	defaults := DefaultConfig()
+	if cfg.EventErrorPolicy == "" {
+		cfg.EventErrorPolicy = defaults.EventErrorPolicy
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.QueueCapacity <= 0 {
+		cfg.QueueCapacity = defaults.QueueCapacity
+	}
+	if cfg.PartitionKey == nil {
+		cfg.PartitionKey = defaults.PartitionKey
+	}
+	if cfg.DefaultRetryPolicy.MaxAttempts <= 0 {
+		cfg.DefaultRetryPolicy = defaults.DefaultRetryPolicy
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = transport.NewJSONCodec()
+	}
+	if cfg.PublishRetryPolicy.MaxAttempts <= 0 {
+		cfg.PublishRetryPolicy = defaults.PublishRetryPolicy
+	}
+
 	bus := &messageBus{
 		handledCommands: make(map[any]commandeventhandler.CommandHandler),
-		handledEvent:    make(map[any]commandeventhandler.EventHandler),
+		handledEvent:    make(map[any][]commandeventhandler.EventHandler),
+		eventTypes:      make(map[string]reflect.Type),
+		config:          cfg,
 		uow:             uow,
 		eventCh:         eventCh,
-		shutdownCh:      make(chan struct{}),
-		closed:          false,
+		stoppedCh:       make(chan struct{}),
+		doneCh:          make(chan struct{}),
 	}
 
 	logging.Info("Message bus initialized").
-		WithInt("event_channel_capacity", 100).
+		WithInt("event_channel_capacity", cap(eventCh)).
+		WithString("event_error_policy", string(cfg.EventErrorPolicy)).
+		WithInt("workers", cfg.Workers).
+		WithInt("worker_queue_capacity", cfg.QueueCapacity).
+		WithInt("default_retry_attempts", cfg.DefaultRetryPolicy.MaxAttempts).
+		WithBool("dead_letter_enabled", cfg.DeadLetterStore != nil).
+		WithInt("transport_topics", len(cfg.EventTopics)).
 		Log()
 
 	// start event handler worker pool
-	// Using a single goroutine to process events sequentially to avoid race conditions
-	// Events are processed one at a time to ensure proper ordering and prevent concurrency issues
-	bus.wg.Add(1)
-	go func(mb *messageBus, evCh chan adapter.EventWithWaitGroup) {
-		defer mb.wg.Done()
-		defer close(evCh)
-		for eventWrapper := range evCh {
-			eventCtx := eventWrapper.Ctx
-			if eventCtx == nil {
-				eventCtx = context.Background()
-			}
-			if err := mb.HandleEvent(eventCtx, eventWrapper.Event); err != nil {
-				logging.Error("Failed to handle event").WithError(err).Log()
-			}
-			// Signal that event is done being handled
-			if eventWrapper.Wg != nil {
-				eventWrapper.Wg.Done()
-			}
-		}
-	}(bus, bus.eventCh)
+	// Events are partitioned by key so events of the same aggregate are handled in order
+	// by the same worker, while unrelated events are handled in parallel
+	bus.startWorkers()
 
 	return bus
 }
 
+// EventName returns the name events are registered and routed under: the stable name from
+// the eventschema registry (or NamedEvent), falling back to the Go type name
+func EventName(event any) string {
+	return eventschema.Name(event)
+}
+
 func (m *messageBus) Uow() adapter.UnitOfWork {
 	return m.uow
 }
 
 func (m *messageBus) AddCommandHandler(handlers ...commandeventhandler.CommandHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, handler := range handlers {
 		cmdName := reflect.TypeOf(handler.NewCommand()).String()
 		if _, ok := m.handledCommands[cmdName]; ok {
@@ -90,27 +181,51 @@ func (m *messageBus) AddCommandHandler(handlers ...commandeventhandler.CommandHa
 	return nil
 }
 
+// AddEventHandler subscribes handlers to their events. Any number of handlers
+// can subscribe to the same event; they run in registration order.
 func (m *messageBus) AddEventHandler(handlers ...commandeventhandler.EventHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, handler := range handlers {
-		eventName := reflect.TypeOf(handler.NewEvent()).String()
-		if _, ok := m.handledEvent[eventName]; ok {
-			return apperrors.Conflict("", fmt.Sprintf("event handler for %s already exists", eventName))
+		event := handler.NewEvent()
+		eventName := EventName(event)
+
+		// Two Go types sharing a name would receive each other's events
+		eventType := reflect.TypeOf(event)
+		if existing, ok := m.eventTypes[eventName]; ok && existing != eventType {
+			return apperrors.Conflict("", fmt.Sprintf("event name %s is used by both %s and %s", eventName, existing, eventType))
 		}
-		m.handledEvent[eventName] = handler
+		m.eventTypes[eventName] = eventType
+
+		m.handledEvent[eventName] = append(m.handledEvent[eventName], handler)
 	}
 
 	return nil
 }
 
 func (m *messageBus) AddCommandMiddleware(middlewares ...commandmiddleware.Middleware) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.commandMiddlewares = append(m.commandMiddlewares, middlewares...)
 	return nil
 }
 
+// AddEventMiddleware adds middlewares wrapping every event handler invocation.
+// With several subscribers, the chain runs once per subscriber.
+func (m *messageBus) AddEventMiddleware(middlewares ...eventmiddleware.Middleware) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.eventMiddlewares = append(m.eventMiddlewares, middlewares...)
+	return nil
+}
+
 func (m *messageBus) Handle(ctx context.Context, cmd any) error {
 	cmdName := reflect.TypeOf(cmd).String()
 
-	handler, ok := m.handledCommands[cmdName]
+	handler, ok := m.commandHandler(cmdName)
 	if !ok {
 		err := fmt.Errorf("command handler for %s not found", cmdName)
 		logging.Error("Command handler not found").
@@ -120,27 +235,74 @@ func (m *messageBus) Handle(ctx context.Context, cmd any) error {
 		return err
 	}
 
+	ctx, done, err := m.acceptWork(ctx)
+	if err != nil {
+		logging.Warn("Command rejected by message bus shutdown").
+			WithAny("command_name", cmdName).
+			Log()
+		return err
+	}
+	defer done()
+
+	// The command causes every event raised while handling it; without a request
+	// correlation ID it also starts a new correlation chain
+	commandID := uuid.New().String()
+	if adapter.CorrelationIDFromContext(ctx) == "" {
+		ctx = adapter.ContextWithCorrelationID(ctx, commandID)
+	}
+	ctx = adapter.ContextWithCausationID(ctx, commandID)
+
 	// Create the base handler function
 	baseHandler := func(ctx context.Context, cmd any) error {
 		return handler.Handle(ctx, cmd)
 	}
 
 	// Apply middlewares using decorator pattern
-	finalHandler := commandmiddleware.ApplyChain(baseHandler, m.commandMiddlewares...)
+	m.mu.RLock()
+	middlewares := m.commandMiddlewares
+	m.mu.RUnlock()
+	finalHandler := commandmiddleware.ApplyChain(baseHandler, middlewares...)
 
 	// Execute the handler with middlewares applied
 	return finalHandler(ctx, cmd)
 }
 
+// commandHandler returns the handler registered for a command name
+func (m *messageBus) commandHandler(cmdName string) (commandeventhandler.CommandHandler, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	handler, ok := m.handledCommands[cmdName]
+	return handler, ok
+}
+
+// HandleEvent runs the handlers of event synchronously, with their middlewares, retry
+// policies and dead-lettering, and returns their errors. Events raised by the unit of work
+// go through EventChannel instead; HandleEvent is for events received from outside the
+// process, such as messages consumed from a broker.
 func (m *messageBus) HandleEvent(ctx context.Context, event any) error {
-	eventName := reflect.TypeOf(event).String()
+	eventName := EventName(event)
+
+	ctx, done, err := m.acceptWork(ctx)
+	if err != nil {
+		logging.Warn("Event rejected by message bus shutdown").
+			WithAny("event_name", eventName).
+			Log()
+		return err
+	}
+	defer done()
+
+	m.mu.RLock()
+	handlers := m.handledEvent[eventName]
+	middlewares := m.eventMiddlewares
+	m.mu.RUnlock()
 
-	logging.Info("Handling event").
+	withEnvelope(ctx, logging.Info("Handling event")).
 		WithAny("event_name", eventName).
+		WithInt("handlers", len(handlers)).
 		Log()
 
-	h, ok := m.handledEvent[eventName]
-	if !ok {
+	if len(handlers) == 0 {
 		err := apperrors.NotFound("", fmt.Sprintf("event handler for %s not found", eventName))
 		logging.Error("Event handler not found").
 			WithAny("event_name", eventName).
@@ -149,54 +311,119 @@ func (m *messageBus) HandleEvent(ctx context.Context, event any) error {
 		return err
 	}
 
-	err := h.Handle(ctx, event)
-	if err != nil {
-		logging.Error("Event handler failed").
-			WithAny("event_name", eventName).
-			WithError(err).
-			Log()
-		return err
+	var errs []error
+	for _, h := range handlers {
+		if err := m.invokeEventHandler(ctx, eventName, h, middlewares, event); err != nil {
+			if m.config.EventErrorPolicy == EventErrorPolicyStop {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 && m.config.EventErrorPolicy == EventErrorPolicyCollect {
+		return errors.Join(errs...)
 	}
 
-	logging.Info("Event handled successfully").
+	logging.Info("Event handled").
 		WithAny("event_name", eventName).
+		WithInt("handlers", len(handlers)).
+		WithInt("failed", len(errs)).
 		Log()
 
 	return nil
 }
 
-// Shutdown gracefully shuts down the message bus.
-// It stops accepting new events, processes remaining events, and closes the event channel.
-func (m *messageBus) Shutdown(ctx context.Context) error {
-	m.mu.Lock()
-	if m.closed {
-		m.mu.Unlock()
-		return nil
+// invokeEventHandler runs a single subscriber through the event middleware chain,
+// retrying it according to the event's retry policy. When all attempts fail the event
+// is dead-lettered (if a store is configured) and the last error is returned.
+func (m *messageBus) invokeEventHandler(
+	ctx context.Context,
+	eventName string,
+	h commandeventhandler.EventHandler,
+	middlewares []eventmiddleware.Middleware,
+	event any,
+) error {
+	handlerName := commandeventhandler.HandlerName(h)
+	start := time.Now()
+
+	// Create the base handler function and apply middlewares using decorator pattern
+	baseHandler := func(ctx context.Context, event any) error {
+		return h.Handle(ctx, event)
+	}
+	finalHandler := eventmiddleware.ApplyChain(baseHandler, middlewares...)
+	ctx = eventmiddleware.ContextWithHandlerName(ctx, handlerName)
+
+	policy := m.config.retryPolicy(eventName)
+	attempts, err := retry.Do(ctx, policy, func(ctx context.Context, attempt int) error {
+		if attempt > 1 {
+			withEnvelope(ctx, logging.Warn("Retrying event handler")).
+				WithAny("event_name", eventName).
+				WithString("handler", handlerName).
+				WithInt("attempt", attempt).
+				WithInt("max_attempts", policy.MaxAttempts).
+				Log()
+		}
+		return finalHandler(ctx, event)
+	})
+	latency := time.Since(start)
+	if err != nil {
+		withEnvelope(ctx, logging.Error("Event handler failed")).
+			WithAny("event_name", eventName).
+			WithString("handler", handlerName).
+			WithInt("attempts", attempts).
+			WithAny("latency", latency).
+			WithError(err).
+			Log()
+
+		m.deadLetter(ctx, eventName, handlerName, event, attempts, err)
+		return fmt.Errorf("event handler %s for %s failed: %w", handlerName, eventName, err)
 	}
-	m.closed = true
-	m.mu.Unlock()
 
-	// Signal shutdown to the event handler goroutine
-	close(m.shutdownCh)
+	withEnvelope(ctx, logging.Info("Event handled successfully")).
+		WithAny("event_name", eventName).
+		WithString("handler", handlerName).
+		WithInt("attempts", attempts).
+		WithAny("latency", latency).
+		Log()
 
-	// Wait for all events to be processed or context timeout
-	done := make(chan struct{})
-	go func() {
-		m.wg.Wait()
-		close(done)
-	}()
+	return nil
+}
 
-	select {
-	case <-done:
-		logging.Info("Message bus shutdown completed successfully").Log()
-		return nil
-	case <-ctx.Done():
-		logging.Warn("Message bus shutdown timed out").WithError(ctx.Err()).Log()
-		return ctx.Err()
+// withEnvelope adds the IDs of the event envelope in ctx to a log entry
+func withEnvelope(ctx context.Context, entry logging.Logger) logging.Logger {
+	if envelope, ok := adapter.EnvelopeFromContext(ctx); ok {
+		entry = entry.
+			WithString("event_id", envelope.EventID).
+			WithString("correlation_id", envelope.CorrelationID).
+			WithString("causation_id", envelope.CausationID)
 	}
+	return entry
 }
 
 // EventChannel returns the event channel for use by unit of work
 func (m *messageBus) EventChannel() chan<- adapter.EventWithWaitGroup {
 	return m.eventCh
 }
+
+// Dispatch handles a command through the bus and returns the typed result of its handler,
+// which must be created with commandeventhandler.NewCommandHandlerWithResult
+func Dispatch[Command any, Result any](ctx context.Context, bus MessageBus, cmd *Command) (Result, error) {
+	var zero Result
+
+	ctx = commandeventhandler.ContextWithCommandResult(ctx, cmd)
+	if err := bus.Handle(ctx, cmd); err != nil {
+		return zero, err
+	}
+
+	result, ok := commandeventhandler.CommandResultFromContext(ctx)
+	if !ok {
+		return zero, fmt.Errorf("command %T did not return a result", cmd)
+	}
+
+	typed, ok := result.(Result)
+	if !ok {
+		return zero, fmt.Errorf("command %T returned %T, expected %T", cmd, result, zero)
+	}
+	return typed, nil
+}
@@ -0,0 +1,104 @@
+package messagebus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	commandeventhandler "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler"
+	querymiddleware "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/command_event_handler/query_middleware"
+)
+
+// QueryBus dispatches queries of the CQRS read side to their handlers and returns results
+type QueryBus interface {
+	AddQueryHandler(handlers ...commandeventhandler.QueryHandler) error
+	AddQueryMiddleware(middlewares ...querymiddleware.Middleware) error
+	Ask(ctx context.Context, query any) (any, error)
+}
+
+type queryBus struct {
+	handledQueries   map[any]commandeventhandler.QueryHandler
+	queryMiddlewares []querymiddleware.Middleware
+	mu               sync.RWMutex
+}
+
+func NewQueryBus() QueryBus {
+	return &queryBus{
+		handledQueries: make(map[any]commandeventhandler.QueryHandler),
+	}
+}
+
+func (q *queryBus) AddQueryHandler(handlers ...commandeventhandler.QueryHandler) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, handler := range handlers {
+		queryName := reflect.TypeOf(handler.NewQuery()).String()
+		if _, ok := q.handledQueries[queryName]; ok {
+			return apperrors.Conflict("", fmt.Sprintf("query handler for %s already exists", queryName))
+		}
+		q.handledQueries[queryName] = handler
+	}
+
+	return nil
+}
+
+func (q *queryBus) AddQueryMiddleware(middlewares ...querymiddleware.Middleware) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queryMiddlewares = append(q.queryMiddlewares, middlewares...)
+	return nil
+}
+
+func (q *queryBus) Ask(ctx context.Context, query any) (any, error) {
+	queryName := reflect.TypeOf(query).String()
+
+	q.mu.RLock()
+	handler, ok := q.handledQueries[queryName]
+	middlewares := q.queryMiddlewares
+	q.mu.RUnlock()
+
+	if !ok {
+		err := fmt.Errorf("query handler for %s not found", queryName)
+		logging.Error("Query handler not found").
+			WithAny("query_name", queryName).
+			WithError(err).
+			Log()
+		return nil, err
+	}
+
+	// Create the base handler function
+	baseHandler := func(ctx context.Context, query any) (any, error) {
+		return handler.Handle(ctx, query)
+	}
+
+	// Apply middlewares using decorator pattern
+	finalHandler := querymiddleware.ApplyChain(baseHandler, middlewares...)
+
+	// Execute the handler with middlewares applied
+	ctx = querymiddleware.ContextWithResultFactory(ctx, handler.NewResult)
+	return finalHandler(ctx, query)
+}
+
+// Ask dispatches a query through the bus and returns its typed result
+func Ask[Result any, Query any](ctx context.Context, bus QueryBus, query *Query) (Result, error) {
+	var zero Result
+
+	result, err := bus.Ask(ctx, query)
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, nil
+	}
+
+	typed, ok := result.(Result)
+	if !ok {
+		return zero, fmt.Errorf("query %T returned %T, expected %T", query, result, zero)
+	}
+	return typed, nil
+}
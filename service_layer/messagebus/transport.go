@@ -0,0 +1,220 @@
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/deadletter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/retry"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/transport"
+)
+
+// publisherName is the handler name under which events that could not be published to
+// topic are dead-lettered; replaying them publishes them again
+func publisherName(topic string) string {
+	return "transport:" + topic
+}
+
+// publish encodes an event raised in this process and publishes it to topic, retrying
+// according to the publish retry policy. When all attempts fail the event is dead-lettered
+// (if a store is configured) and the last error is returned.
+func (m *messageBus) publish(ctx context.Context, topic string, event any) error {
+	eventName := EventName(event)
+	envelope, ok := adapter.EnvelopeFromContext(ctx)
+	if !ok {
+		envelope = adapter.NewEventEnvelope(ctx, event)
+	}
+
+	attempts, err := m.publishEnvelope(ctx, topic, envelope, event)
+	if err != nil {
+		withEnvelope(ctx, logging.Error("Event publishing failed")).
+			WithAny("event_name", eventName).
+			WithString("topic", topic).
+			WithString("transport", m.config.Transport.Name()).
+			WithInt("attempts", attempts).
+			WithError(err).
+			Log()
+
+		m.deadLetter(ctx, eventName, publisherName(topic), event, attempts, err)
+		return fmt.Errorf("publishing %s to %s failed: %w", eventName, topic, err)
+	}
+
+	withEnvelope(ctx, logging.Info("Event published")).
+		WithAny("event_name", eventName).
+		WithString("topic", topic).
+		WithString("transport", m.config.Transport.Name()).
+		WithInt("attempts", attempts).
+		Log()
+
+	return nil
+}
+
+// publishEnvelope encodes event with its envelope and publishes it, returning the number of attempts made
+func (m *messageBus) publishEnvelope(ctx context.Context, topic string, envelope adapter.EventEnvelope, event any) (int, error) {
+	body, err := m.config.Codec.Encode(envelope, event)
+	if err != nil {
+		return 1, err
+	}
+
+	msg := transport.Message{
+		Topic: topic,
+		Key:   m.partitionKey(event, envelope),
+		Body:  body,
+	}
+	return retry.Do(ctx, m.config.PublishRetryPolicy, func(ctx context.Context, attempt int) error {
+		if attempt > 1 {
+			withEnvelope(ctx, logging.Warn("Retrying event publishing")).
+				WithString("event_name", envelope.EventType).
+				WithString("topic", topic).
+				WithInt("attempt", attempt).
+				WithInt("max_attempts", m.config.PublishRetryPolicy.MaxAttempts).
+				Log()
+		}
+		return m.config.Transport.Publish(ctx, msg)
+	})
+}
+
+// subscribe subscribes to the topics of the events this process has handlers for. Messages
+// are handled like events raised in process, with the same retry policies and dead-lettering.
+func (m *messageBus) subscribe() {
+	if m.config.Transport == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed || m.unsubscribe != nil {
+		return
+	}
+
+	topics := make(map[string]bool)
+	for eventName, topic := range m.config.EventTopics {
+		if len(m.handledEvent[eventName]) > 0 {
+			topics[topic] = true
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.unsubscribe = cancel
+
+	for topic := range topics {
+		m.subscriptions.Add(1)
+		go func(topic string) {
+			defer m.subscriptions.Done()
+
+			logging.Info("Subscribing to event topic").
+				WithString("topic", topic).
+				WithString("transport", m.config.Transport.Name()).
+				Log()
+
+			if err := m.config.Transport.Subscribe(ctx, topic, m.handleTransportMessage); err != nil {
+				logging.Error("Event topic subscription failed").
+					WithString("topic", topic).
+					WithString("transport", m.config.Transport.Name()).
+					WithError(err).
+					Log()
+			}
+		}(topic)
+	}
+}
+
+// stopSubscriptions stops receiving messages from the transport; messages being handled are not interrupted
+func (m *messageBus) stopSubscriptions() {
+	if m.unsubscribe != nil {
+		m.unsubscribe()
+	}
+}
+
+// handleTransportMessage decodes a message received from the transport and runs the handlers
+// of its event. Events without handlers in this process, sharing a topic with those it
+// handles, are skipped.
+func (m *messageBus) handleTransportMessage(ctx context.Context, msg transport.Message) error {
+	var unhandled string
+	newEvent := func(name string) (any, bool) {
+		m.mu.RLock()
+		handlers := len(m.handledEvent[name])
+		m.mu.RUnlock()
+		if handlers == 0 {
+			unhandled = name
+			return nil, false
+		}
+		return m.newEvent(name)
+	}
+
+	envelope, event, err := m.config.Codec.Decode(msg.Body, newEvent)
+	if unhandled != "" {
+		logging.Debug("Skipping event without handlers").
+			WithString("event_name", unhandled).
+			WithString("topic", msg.Topic).
+			Log()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode message from %s: %w", msg.Topic, err)
+	}
+
+	// Stopping the subscription does not cancel the handlers already running
+	ctx = envelopeContext(context.WithoutCancel(ctx), adapter.EventWithWaitGroup{Event: event, Envelope: envelope})
+	return m.HandleEvent(ctx, event)
+}
+
+// newEvent returns a pointer to a new value of the Go type registered under an event name
+func (m *messageBus) newEvent(name string) (any, bool) {
+	m.mu.RLock()
+	t, ok := m.eventTypes[name]
+	m.mu.RUnlock()
+	if !ok {
+		return eventschema.New(name)
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// replayPublish publishes a dead-lettered event that could not be published to topic again
+func (m *messageBus) replayPublish(ctx context.Context, deadLetter *deadletter.DeadLetter, topic string) error {
+	event, ok := m.newEvent(deadLetter.EventType)
+	if !ok {
+		return fmt.Errorf("no Go type registered for event type %s", deadLetter.EventType)
+	}
+	if err := eventschema.DecodeInto(deadLetter.EventType, deadLetter.EventVersion, []byte(deadLetter.Payload), event); err != nil {
+		return fmt.Errorf("failed to decode dead letter %d: %w", deadLetter.ID, err)
+	}
+
+	// The replayed event keeps its ID and correlation chain so subscribers can recognize it
+	if deadLetter.CorrelationID != "" {
+		ctx = adapter.ContextWithCorrelationID(ctx, deadLetter.CorrelationID)
+	}
+	envelope := adapter.NewEventEnvelope(ctx, event)
+	if deadLetter.EventID != "" {
+		envelope.EventID = deadLetter.EventID
+	}
+
+	logging.Info("Replaying dead-lettered event publishing").
+		WithUint("dead_letter_id", uint(deadLetter.ID)).
+		WithString("event_name", deadLetter.EventType).
+		WithString("topic", topic).
+		Log()
+
+	store := m.config.DeadLetterStore
+	if _, err := m.publishEnvelope(ctx, topic, envelope, event); err != nil {
+		logging.Error("Dead letter replay failed").
+			WithUint("dead_letter_id", uint(deadLetter.ID)).
+			WithError(err).
+			Log()
+		if markErr := store.MarkReplayFailed(ctx, deadLetter.ID, err.Error()); markErr != nil {
+			return errors.Join(err, markErr)
+		}
+		return err
+	}
+
+	return store.MarkReplayed(ctx, deadLetter.ID)
+}
@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/messagebus"
+)
+
+// BusEventHandlerConfig holds configuration for the message bus event handler
+type BusEventHandlerConfig struct {
+	// EventTypes maps outbox event types to values of the Go type their payload is decoded
+	// into, e.g. {"OrderPlaced": &OrderPlaced{}}. Event types registered in the eventschema
+	// registry do not need an entry.
+	EventTypes map[string]any
+	// IgnoreUnknown skips messages whose event type has no Go type instead of failing them
+	IgnoreUnknown bool
+}
+
+// BusEventHandler implements EventHandler by decoding consumed payloads into their Go type
+// and handling them with the typed event handlers of the message bus. The envelope of the
+// consumed message is in the context handlers receive (see adapter.EnvelopeFromContext).
+type BusEventHandler struct {
+	bus    messagebus.EventBus
+	types  map[string]reflect.Type
+	config BusEventHandlerConfig
+}
+
+// NewBusEventHandler creates an event handler dispatching consumed events through bus, e.g.
+// the bus returned by messagebus.NewMessageBus
+func NewBusEventHandler(bus messagebus.EventBus, cfg BusEventHandlerConfig) *BusEventHandler {
+	types := make(map[string]reflect.Type, len(cfg.EventTypes))
+	for eventType, sample := range cfg.EventTypes {
+		t := reflect.TypeOf(sample)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		types[eventType] = t
+	}
+
+	return &BusEventHandler{
+		bus:    bus,
+		types:  types,
+		config: cfg,
+	}
+}
+
+// HandleEvent decodes payload into the Go type of eventType and handles it through the bus
+func (h *BusEventHandler) HandleEvent(ctx context.Context, eventType string, payload map[string]interface{}) error {
+	event, ok := h.newEvent(eventType)
+	if !ok {
+		if h.config.IgnoreUnknown {
+			logging.Debug("Skipping consumed event of unknown type").
+				WithString("event_type", eventType).
+				Log()
+			return nil
+		}
+		return apperrors.NotFound("", fmt.Sprintf("no Go type registered for event type %s", eventType))
+	}
+
+	// The payload was already upcast to the current version by the consumer
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload of %s: %w", eventType, err)
+	}
+	if err := json.Unmarshal(data, event); err != nil {
+		return apperrors.Validation("", fmt.Sprintf("failed to decode payload of %s: %v", eventType, err))
+	}
+
+	return h.bus.HandleEvent(ctx, event)
+}
+
+// newEvent returns a pointer to a new value of the Go type of eventType
+func (h *BusEventHandler) newEvent(eventType string) (any, bool) {
+	if t, ok := h.types[eventType]; ok {
+		return reflect.New(t).Interface(), true
+	}
+	return eventschema.New(eventType)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
 	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
 
 	"github.com/IBM/sarama"
 )
@@ -91,6 +95,66 @@ func (c *Consumer) handleMessage(ctx context.Context, message *sarama.ConsumerMe
 		return fmt.Errorf("payload is missing or invalid")
 	}
 
+	// Messages published before the event schema changed are upcast to its current version
+	version := 0
+	if v, ok := kafkaMessage["event_version"].(float64); ok {
+		version = int(v)
+	}
+	payload, err := eventschema.Upcast(eventType, version, payload)
+	if err != nil {
+		return err
+	}
+
+	// Continue the trace and correlation chain of the producer; the consumed event causes
+	// whatever the handler does, and handlers find its original envelope in ctx
+	envelope := envelopeFromMessage(kafkaMessage, eventType, version)
+	ctx = envelope.ContextWithTrace(ctx)
+	ctx = adapter.ContextWithEnvelope(ctx, envelope)
+	if envelope.CorrelationID != "" {
+		ctx = adapter.ContextWithCorrelationID(ctx, envelope.CorrelationID)
+	}
+	if envelope.EventID != "" {
+		ctx = adapter.ContextWithCausationID(ctx, envelope.EventID)
+	}
+
 	// Delegate to the event handler
 	return c.handler.HandleEvent(ctx, eventType, payload)
 }
+
+// envelopeFromMessage restores the envelope of an event from a message written by the outbox processor
+func envelopeFromMessage(message map[string]interface{}, eventType string, version int) adapter.EventEnvelope {
+	str := func(key string) string {
+		s, _ := message[key].(string)
+		return s
+	}
+
+	envelope := adapter.EventEnvelope{
+		EventID:       str("envelope_event_id"),
+		EventType:     eventType,
+		EventVersion:  version,
+		AggregateType: str("aggregate_type"),
+		AggregateID:   str("aggregate_id"),
+		CorrelationID: str("correlation_id"),
+		CausationID:   str("causation_id"),
+	}
+	// Events stored before envelopes existed are identified by their outbox ID
+	if envelope.EventID == "" {
+		if id, ok := message["event_id"].(float64); ok {
+			envelope.EventID = strconv.FormatUint(uint64(id), 10)
+		}
+	}
+	if occurredAt, err := time.Parse(time.RFC3339Nano, str("occurred_at")); err == nil {
+		envelope.OccurredAt = occurredAt
+	}
+
+	if metadata, ok := message["metadata"].(map[string]interface{}); ok {
+		envelope.Metadata = make(map[string]string, len(metadata))
+		for key, value := range metadata {
+			if s, ok := value.(string); ok {
+				envelope.Metadata[key] = s
+			}
+		}
+	}
+
+	return envelope
+}
@@ -1,12 +1,14 @@
 package outbox
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
 
 	"gorm.io/gorm"
 )
@@ -63,9 +65,15 @@ type OutboxEvent struct {
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	DeletedAt     gorm.DeletedAt    `gorm:"index"`
+	EventID       string            `json:"event_id" gorm:"event_id;index"`
 	EventType     string            `json:"event_type" gorm:"event_type"`
+	EventVersion  int               `json:"event_version" gorm:"event_version;default:1"` // Schema version of the payload
 	AggregateType string            `json:"aggregate_type" gorm:"aggregate_type"`
 	AggregateID   string            `json:"aggregate_id" gorm:"aggregate_id"`
+	CorrelationID string            `json:"correlation_id" gorm:"correlation_id;index"`
+	CausationID   string            `json:"causation_id" gorm:"causation_id"`
+	OccurredAt    time.Time         `json:"occurred_at" gorm:"occurred_at"`
+	Metadata      JSONBMap          `json:"metadata" gorm:"type:jsonb"` // Trace context and other envelope metadata
 	Payload       JSONBMap          `json:"payload" gorm:"type:jsonb"`
 	Status        OutboxEventStatus `json:"status" gorm:"status;default:'pending'"`
 	RetryCount    int               `json:"retry_count" gorm:"retry_count;default:0"`
@@ -74,6 +82,44 @@ type OutboxEvent struct {
 	ProcessedAt   *time.Time        `json:"processed_at,omitempty" gorm:"processed_at"`
 }
 
+// NewOutboxEvent creates a pending outbox event for a domain event. When called from an
+// event handler, the event ID, aggregate, occurred-at time, correlation and causation IDs
+// are taken from the envelope of the event being handled. The payload is stored with the
+// current schema version of eventType, so consumers can upcast it after the event changes.
+func NewOutboxEvent(ctx context.Context, eventType string, payload JSONBMap) *OutboxEvent {
+	envelope, ok := adapter.EnvelopeFromContext(ctx)
+	if !ok {
+		envelope = adapter.NewEventEnvelope(ctx, nil)
+	}
+
+	return &OutboxEvent{
+		EventID:       envelope.EventID,
+		EventType:     eventType,
+		EventVersion:  eventschema.CurrentVersion(eventType),
+		AggregateType: envelope.AggregateType,
+		AggregateID:   envelope.AggregateID,
+		CorrelationID: envelope.CorrelationID,
+		CausationID:   envelope.CausationID,
+		OccurredAt:    envelope.OccurredAt,
+		Metadata:      metadataMap(envelope.Metadata),
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+	}
+}
+
+func metadataMap(metadata map[string]string) JSONBMap {
+	result := make(JSONBMap, len(metadata))
+	for key, value := range metadata {
+		result[key] = value
+	}
+	return result
+}
+
+// GetID returns the outbox event ID as uint64
+func (o *OutboxEvent) GetID() uint64 {
+	return uint64(o.ID)
+}
+
 // TableName returns the table name for the outbox event
 // This can be overridden by modules if they need different table names
 func (o *OutboxEvent) TableName() string {
@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/ali-mahdavi-dev/shikposh-framework/service_layer/outbox"
+
+// RegisterMetrics registers gauges reporting the number of pending and failed outbox events.
+// The counts are queried from the repository on every collection; call Unregister on the
+// returned registration to stop reporting. repo must implement StatusCounter.
+func RegisterMetrics(repo Repository) (metric.Registration, error) {
+	counter, ok := repo.(StatusCounter)
+	if !ok {
+		return nil, fmt.Errorf("outbox repository %T does not implement StatusCounter", repo)
+	}
+
+	meter := otel.Meter(meterName)
+
+	pending, err := meter.Int64ObservableGauge("outbox.events.pending",
+		metric.WithDescription("Number of outbox events waiting to be published"))
+	if err != nil {
+		return nil, err
+	}
+	failed, err := meter.Int64ObservableGauge("outbox.events.failed",
+		metric.WithDescription("Number of outbox events that exhausted their retries"))
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		pendingCount, err := counter.CountByStatus(ctx, OutboxStatusPending)
+		if err != nil {
+			return err
+		}
+		failedCount, err := counter.CountByStatus(ctx, OutboxStatusFailed)
+		if err != nil {
+			return err
+		}
+
+		o.ObserveInt64(pending, pendingCount)
+		o.ObserveInt64(failed, failedCount)
+		return nil
+	}, pending, failed)
+}
@@ -133,15 +133,24 @@ func (p *Processor) processEvent(ctx context.Context, event *OutboxEvent) error
 		return fmt.Errorf("failed to mark event as processing: %w", err)
 	}
 
-	// Prepare message
+	// Prepare message. event_id stays the outbox row ID that existing consumers parse and
+	// dedupe on; the envelope ID is published next to it.
 	message := map[string]interface{}{
 		"event_id":       event.ID,
 		"event_type":     event.EventType,
+		"event_version":  event.EventVersion,
 		"aggregate_type": event.AggregateType,
 		"aggregate_id":   event.AggregateID,
+		"correlation_id": event.CorrelationID,
+		"causation_id":   event.CausationID,
+		"metadata":       map[string]interface{}(event.Metadata),
 		"payload":        map[string]interface{}(event.Payload), // Convert JSONBMap to map[string]interface{}
+		"occurred_at":    event.OccurredAt,
 		"created_at":     event.CreatedAt,
 	}
+	if event.EventID != "" {
+		message["envelope_event_id"] = event.EventID
+	}
 
 	// Send to message broker
 	if err := p.pub.SendMessage(p.config.Topic, message); err != nil {
@@ -157,6 +166,7 @@ func (p *Processor) processEvent(ctx context.Context, event *OutboxEvent) error
 		WithInt64("event_id", int64(event.ID)).
 		WithString("event_type", event.EventType).
 		WithString("aggregate_id", event.AggregateID).
+		WithString("correlation_id", event.CorrelationID).
 		Log()
 
 	return nil
@@ -21,6 +21,12 @@ type Repository interface {
 	IncrementRetry(ctx context.Context, id OutboxEventID) error
 }
 
+// StatusCounter is implemented by repositories that can count outbox events by status, as
+// RegisterMetrics requires
+type StatusCounter interface {
+	CountByStatus(ctx context.Context, status OutboxEventStatus) (int64, error)
+}
+
 // GormRepository is a GORM implementation of the outbox repository
 type GormRepository struct {
 	adapter.BaseRepository[*OutboxEvent]
@@ -96,3 +102,11 @@ func (r *GormRepository) IncrementRetry(ctx context.Context, id OutboxEventID) e
 		Where("id = ?", uint64(id)).
 		Update("retry_count", gorm.Expr("retry_count + 1")).Error
 }
+
+func (r *GormRepository) CountByStatus(ctx context.Context, status OutboxEventStatus) (int64, error) {
+	var count int64
+	err := r.Model(ctx).
+		Where("status = ?", status).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+)
+
+// Policy describes how an operation is retried
+type Policy struct {
+	MaxAttempts    int                  // Total number of attempts, including the first one
+	InitialBackoff time.Duration        // Delay before the second attempt
+	MaxBackoff     time.Duration        // Upper bound for the delay between attempts
+	Multiplier     float64              // Factor applied to the delay after each attempt
+	Jitter         float64              // Random fraction (0.0 to 1.0) added to or removed from each delay
+	Retryable      func(err error) bool // Classifies errors; nil uses IsRetryable
+}
+
+// DefaultPolicy returns a policy with 3 attempts and exponential backoff with jitter
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// NoRetry returns a policy that runs the operation exactly once
+func NoRetry() Policy {
+	return Policy{MaxAttempts: 1}
+}
+
+// Backoff returns the delay to wait after the given (1-based) failed attempt
+func (p Policy) Backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// ShouldRetry reports whether err is worth another attempt under this policy
+func (p Policy) ShouldRetry(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// Do runs fn until it succeeds, the error is not retryable, attempts are exhausted or ctx is done.
+// fn receives the 1-based attempt number. Do returns the number of attempts made and the last error.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context, attempt int) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx, attempt); err == nil {
+			return attempt, nil
+		}
+		if attempt >= maxAttempts || !p.ShouldRetry(err) {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// permanentError marks an error as not retryable
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that IsRetryable reports false for it
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsRetryable is the default error classification. Errors marked Permanent, context
+// cancellation and client-side application errors (validation, not found, conflict,
+// unauthorized, forbidden) are not retried; everything else is.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if appErr, ok := apperrors.As(err); ok {
+		switch appErr.Type() {
+		case apperrors.ErrorTypeValidation,
+			apperrors.ErrorTypeNotFound,
+			apperrors.ErrorTypeConflict,
+			apperrors.ErrorTypeUnauthorized,
+			apperrors.ErrorTypeForbidden,
+			apperrors.ErrorTypeMethodNotAllowed,
+			apperrors.ErrorTypeTooLarge:
+			return false
+		}
+	}
+
+	return true
+}
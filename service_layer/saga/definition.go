@@ -0,0 +1,244 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Definition is a saga registered with the Manager; it is implemented by *Saga
+type Definition interface {
+	Name() string
+
+	steps() []stepInfo
+	bindings() []binding
+	runAction(ctx context.Context, step int, data string) (string, error)
+	runCompensation(ctx context.Context, step int, data string) (string, error)
+}
+
+// stepInfo describes a step independently of the saga data type
+type stepInfo struct {
+	name    string
+	waits   bool // The step completes when an event bound with CompletedBy arrives
+	timeout time.Duration
+}
+
+type bindingKind int
+
+const (
+	bindingStart bindingKind = iota
+	bindingComplete
+	bindingFail
+)
+
+// binding routes an event type to a saga
+type binding struct {
+	kind     bindingKind
+	step     string
+	newEvent func() any
+	key      func(event any) string
+	apply    func(ctx context.Context, event any, data string) (string, error)
+	reason   func(event any) string
+}
+
+// Saga is a sequence of steps, each with an action and a compensation, carrying data of type
+// D between them. Steps either complete when their action returns or, when bound with
+// CompletedBy, when an event reports the outcome of the work the action requested.
+// When a step fails, the compensations of the steps executed before it run in reverse order.
+type Saga[D any] struct {
+	name      string
+	stepList  []*step[D]
+	eventList []binding
+}
+
+type step[D any] struct {
+	name         string
+	action       func(ctx context.Context, data *D) error
+	compensation func(ctx context.Context, data *D) error
+	timeout      time.Duration
+	waits        bool
+}
+
+// StepOption configures a saga step
+type StepOption func(*stepOptions)
+
+type stepOptions struct {
+	timeout time.Duration
+}
+
+// WithTimeout compensates the saga when the event completing the step does not arrive
+// within d of the step action
+func WithTimeout(d time.Duration) StepOption {
+	return func(o *stepOptions) {
+		o.timeout = d
+	}
+}
+
+// New creates a saga named name, e.g. "checkout"
+func New[D any](name string) *Saga[D] {
+	return &Saga[D]{name: name}
+}
+
+// Name returns the name of the saga
+func (s *Saga[D]) Name() string {
+	return s.name
+}
+
+// Step appends a step. action typically dispatches a command with Dispatch; compensation,
+// which may be nil, undoes it. Actions run at least once: a saga resumed after a crash runs
+// the action of its current step again. Compensations may run after a timeout without
+// knowing whether the requested work happened. Both should therefore be idempotent.
+func (s *Saga[D]) Step(
+	name string,
+	action func(ctx context.Context, data *D) error,
+	compensation func(ctx context.Context, data *D) error,
+	opts ...StepOption,
+) *Saga[D] {
+	var options stepOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	s.stepList = append(s.stepList, &step[D]{
+		name:         name,
+		action:       action,
+		compensation: compensation,
+		timeout:      options.timeout,
+	})
+	return s
+}
+
+func (s *Saga[D]) findStep(name string) *step[D] {
+	for _, st := range s.stepList {
+		if st.name == name {
+			return st
+		}
+	}
+	panic(fmt.Sprintf("saga %s has no step %s", s.name, name))
+}
+
+// StartedBy starts a new instance of s for every event of type E. key returns the
+// correlation key that later events are matched on; init, which may be nil, fills the saga
+// data from the event. Events for a key whose saga has not finished are ignored; once it
+// finished, the next event for the key starts a new instance.
+func StartedBy[E any, D any](s *Saga[D], key func(event *E) string, init func(ctx context.Context, event *E, data *D) error) *Saga[D] {
+	s.eventList = append(s.eventList, binding{
+		kind:     bindingStart,
+		newEvent: func() any { return new(E) },
+		key:      func(event any) string { return key(event.(*E)) },
+		apply:    applyFunc(init),
+	})
+	return s
+}
+
+// CompletedBy makes the named step wait for an event of type E before the saga moves on.
+// apply, which may be nil, copies the outcome of the step from the event to the saga data.
+func CompletedBy[E any, D any](s *Saga[D], stepName string, key func(event *E) string, apply func(ctx context.Context, event *E, data *D) error) *Saga[D] {
+	s.findStep(stepName).waits = true
+	s.eventList = append(s.eventList, binding{
+		kind:     bindingComplete,
+		step:     stepName,
+		newEvent: func() any { return new(E) },
+		key:      func(event any) string { return key(event.(*E)) },
+		apply:    applyFunc(apply),
+	})
+	return s
+}
+
+// FailedBy compensates the saga when an event of type E reports that the work requested by
+// the named step failed. The step itself is not compensated. reason, which may be nil,
+// describes the failure.
+func FailedBy[E any, D any](s *Saga[D], stepName string, key func(event *E) string, reason func(event *E) string) *Saga[D] {
+	s.findStep(stepName)
+	b := binding{
+		kind:     bindingFail,
+		step:     stepName,
+		newEvent: func() any { return new(E) },
+		key:      func(event any) string { return key(event.(*E)) },
+	}
+	if reason != nil {
+		b.reason = func(event any) string { return reason(event.(*E)) }
+	}
+	s.eventList = append(s.eventList, b)
+	return s
+}
+
+func applyFunc[E any, D any](fn func(ctx context.Context, event *E, data *D) error) func(context.Context, any, string) (string, error) {
+	return func(ctx context.Context, event any, raw string) (string, error) {
+		if fn == nil {
+			return raw, nil
+		}
+		return withData(raw, func(data *D) error {
+			return fn(ctx, event.(*E), data)
+		})
+	}
+}
+
+func (s *Saga[D]) steps() []stepInfo {
+	infos := make([]stepInfo, len(s.stepList))
+	for i, st := range s.stepList {
+		infos[i] = stepInfo{
+			name:    st.name,
+			waits:   st.waits,
+			timeout: st.timeout,
+		}
+	}
+	return infos
+}
+
+func (s *Saga[D]) bindings() []binding {
+	return s.eventList
+}
+
+func (s *Saga[D]) runAction(ctx context.Context, i int, raw string) (string, error) {
+	st := s.stepList[i]
+	if st.action == nil {
+		return raw, nil
+	}
+	return withData(raw, func(data *D) error {
+		return st.action(ctx, data)
+	})
+}
+
+func (s *Saga[D]) runCompensation(ctx context.Context, i int, raw string) (string, error) {
+	st := s.stepList[i]
+	if st.compensation == nil {
+		return raw, nil
+	}
+	return withData(raw, func(data *D) error {
+		return st.compensation(ctx, data)
+	})
+}
+
+// withData decodes the saga data, runs fn on it and encodes it again
+func withData[D any](raw string, fn func(data *D) error) (string, error) {
+	data := new(D)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), data); err != nil {
+			return raw, fmt.Errorf("failed to decode saga data: %w", err)
+		}
+	}
+
+	if err := fn(data); err != nil {
+		return raw, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return raw, fmt.Errorf("failed to encode saga data: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// DecodeData returns the data of a saga instance, e.g. to inspect an in-flight saga
+func DecodeData[D any](instance *Instance) (*D, error) {
+	data := new(D)
+	if instance.Data == "" {
+		return data, nil
+	}
+	if err := json.Unmarshal([]byte(instance.Data), data); err != nil {
+		return nil, fmt.Errorf("failed to decode saga data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,31 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/jobs"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/messagebus"
+)
+
+type busKey struct{}
+
+// Dispatch sends cmd from a step action or compensation. The command is enqueued on the
+// job queue of the message bus (see JobEnqueuer.Enqueue) rather than handled in place: steps
+// run inside event handlers, and a command handled there holds the event worker until the
+// events it raises are handled, which may include the event the saga waits for. The
+// message bus must implement messagebus.JobEnqueuer and be configured with a JobStore.
+func Dispatch(ctx context.Context, cmd any, opts ...jobs.EnqueueOption) error {
+	bus, ok := ctx.Value(busKey{}).(messagebus.MessageBus)
+	if !ok {
+		return errors.New("saga.Dispatch called outside of a saga step")
+	}
+	enqueuer, ok := bus.(messagebus.JobEnqueuer)
+	if !ok {
+		return fmt.Errorf("message bus %T cannot enqueue commands", bus)
+	}
+
+	_, err := enqueuer.Enqueue(ctx, cmd, opts...)
+	return err
+}
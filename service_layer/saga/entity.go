@@ -0,0 +1,61 @@
+package saga
+
+import (
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+)
+
+// SagaStatus represents the status of a saga instance
+type SagaStatus string
+
+const (
+	SagaStatusRunning      SagaStatus = "running"      // Executing steps
+	SagaStatusWaiting      SagaStatus = "waiting"      // Waiting for the event completing the current step
+	SagaStatusCompleted    SagaStatus = "completed"    // Every step completed
+	SagaStatusCompensating SagaStatus = "compensating" // Undoing the executed steps
+	SagaStatusCompensated  SagaStatus = "compensated"  // Every executed step was compensated
+	SagaStatusFailed       SagaStatus = "failed"       // A compensation failed; needs manual attention
+)
+
+// SagaID is the type for saga instance ID
+type SagaID uint64
+
+// Instance is the persisted state of one run of a saga
+type Instance struct {
+	adapter.BaseEntity
+	ID              SagaID `gorm:"primaryKey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	SagaType        string     `json:"saga_type" gorm:"saga_type;index:idx_saga_correlation;uniqueIndex:idx_saga_active"`
+	CorrelationKey  string     `json:"correlation_key" gorm:"correlation_key;index:idx_saga_correlation"` // Business key events are matched on, e.g. the order ID
+	ActiveKey       *string    `json:"-" gorm:"active_key;uniqueIndex:idx_saga_active"`                   // CorrelationKey until the saga finishes, so only one unfinished instance exists per key
+	Status          SagaStatus `json:"status" gorm:"status;index"`
+	CurrentStep     int        `json:"current_step" gorm:"current_step"`
+	CurrentStepName string     `json:"current_step_name" gorm:"current_step_name"`
+	ExecutedSteps   int        `json:"executed_steps" gorm:"executed_steps"` // Number of steps whose action ran and must be compensated on failure
+	Data            string     `json:"data" gorm:"type:text"`                // JSON of the saga data
+	LastError       *string    `json:"last_error,omitempty" gorm:"last_error;type:text"`
+	DeadlineAt      *time.Time `json:"deadline_at,omitempty" gorm:"deadline_at;index"` // When the current step times out, or a running or compensating saga counts as stalled
+	FinishedAt      *time.Time `json:"finished_at,omitempty" gorm:"finished_at"`
+	Version         int        `json:"version" gorm:"version;default:0"`
+}
+
+// GetID returns the saga ID as uint64
+func (i *Instance) GetID() uint64 {
+	return uint64(i.ID)
+}
+
+// TableName returns the table name for the saga instance
+func (i *Instance) TableName() string {
+	return "saga_instances"
+}
+
+// Done reports whether the saga reached a final status
+func (i *Instance) Done() bool {
+	switch i.Status {
+	case SagaStatusCompleted, SagaStatusCompensated, SagaStatusFailed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,556 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/authorization"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/messagebus"
+)
+
+// Config holds configuration for the saga manager
+type Config struct {
+	DefaultStepTimeout   time.Duration // Timeout of waiting steps without WithTimeout; 0 waits forever
+	RecoveryTimeout      time.Duration // How long a running or compensating saga may go unsaved before it is resumed
+	TimeoutCheckInterval time.Duration // How often timed out steps and stalled sagas are looked for
+	TimeoutBatchSize     int           // Maximum number of timed out or stalled sagas handled per check
+}
+
+// DefaultConfig returns default configuration for the saga manager
+func DefaultConfig() Config {
+	return Config{
+		RecoveryTimeout:      5 * time.Minute,
+		TimeoutCheckInterval: 10 * time.Second,
+		TimeoutBatchSize:     100,
+	}
+}
+
+// Manager runs sagas: it subscribes them to their events on the message bus, persists their
+// state through the unit of work, compensates sagas whose steps time out and resumes sagas
+// left running or compensating, e.g. by a crash.
+// It implements service_host.Service for the timeout checks.
+type Manager struct {
+	bus    messagebus.MessageBus
+	store  Store
+	config Config
+
+	mu    sync.RWMutex
+	sagas map[string]Definition
+
+	stopOnce sync.Once
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewManager creates a new saga manager
+func NewManager(bus messagebus.MessageBus, store Store, cfg Config) *Manager {
+	defaults := DefaultConfig()
+	if cfg.RecoveryTimeout <= 0 {
+		cfg.RecoveryTimeout = defaults.RecoveryTimeout
+	}
+	if cfg.TimeoutCheckInterval <= 0 {
+		cfg.TimeoutCheckInterval = defaults.TimeoutCheckInterval
+	}
+	if cfg.TimeoutBatchSize <= 0 {
+		cfg.TimeoutBatchSize = defaults.TimeoutBatchSize
+	}
+
+	return &Manager{
+		bus:      bus,
+		store:    store,
+		config:   cfg,
+		sagas:    make(map[string]Definition),
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Register adds sagas and subscribes them to the events that start, advance and fail them
+func (m *Manager) Register(sagas ...Definition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, def := range sagas {
+		if _, exists := m.sagas[def.Name()]; exists {
+			return fmt.Errorf("saga %s already registered", def.Name())
+		}
+		if len(def.steps()) == 0 {
+			return fmt.Errorf("saga %s has no steps", def.Name())
+		}
+
+		handlers := make([]*eventHandler, 0, len(def.bindings()))
+		for _, b := range def.bindings() {
+			handlers = append(handlers, &eventHandler{manager: m, saga: def, binding: b})
+		}
+		for _, h := range handlers {
+			if err := m.bus.AddEventHandler(h); err != nil {
+				return err
+			}
+		}
+
+		m.sagas[def.Name()] = def
+	}
+
+	return nil
+}
+
+// Get returns a saga instance
+func (m *Manager) Get(ctx context.Context, id SagaID) (*Instance, error) {
+	return m.store.Get(ctx, id)
+}
+
+// List returns saga instances, e.g. the in-flight ones with Status SagaStatusWaiting
+func (m *Manager) List(ctx context.Context, filter ListFilter) ([]*Instance, error) {
+	return m.store.List(ctx, filter)
+}
+
+// eventHandler routes one event type bound to a saga to the manager
+type eventHandler struct {
+	manager *Manager
+	saga    Definition
+	binding binding
+}
+
+func (h *eventHandler) HandlerName() string {
+	return "saga:" + h.saga.Name() + ":" + messagebus.EventName(h.binding.newEvent())
+}
+
+func (h *eventHandler) NewEvent() any {
+	return h.binding.newEvent()
+}
+
+func (h *eventHandler) Handle(ctx context.Context, event any) error {
+	switch h.binding.kind {
+	case bindingStart:
+		return h.manager.start(ctx, h.saga, h.binding, event)
+	case bindingComplete:
+		return h.manager.complete(ctx, h.saga, h.binding, event)
+	default:
+		return h.manager.fail(ctx, h.saga, h.binding, event)
+	}
+}
+
+func (m *Manager) start(ctx context.Context, def Definition, b binding, event any) error {
+	key := b.key(event)
+
+	started, err := m.inProgress(ctx, def, key)
+	if err != nil {
+		return err
+	}
+	if started {
+		logging.Debug("Saga already started").
+			WithString("saga", def.Name()).
+			WithString("correlation_key", key).
+			Log()
+		return nil
+	}
+
+	data, err := b.apply(ctx, event, "")
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.config.RecoveryTimeout)
+	instance := &Instance{
+		SagaType:       def.Name(),
+		CorrelationKey: key,
+		Status:         SagaStatusRunning,
+		Data:           data,
+		DeadlineAt:     &deadline,
+	}
+	err = m.bus.Uow().Do(ctx, func(ctx context.Context) error {
+		return m.store.Create(ctx, instance)
+	})
+	if err != nil {
+		// A concurrent start event for the same key created the instance first, violating
+		// the unique active key; retrying the event would only fail the same way
+		if started, findErr := m.inProgress(ctx, def, key); findErr == nil && started {
+			logging.Debug("Saga already started").
+				WithString("saga", def.Name()).
+				WithString("correlation_key", key).
+				Log()
+			return nil
+		}
+		return err
+	}
+
+	logging.Info("Saga started").
+		WithString("saga", def.Name()).
+		WithString("correlation_key", key).
+		WithAny("saga_id", instance.ID).
+		Log()
+
+	return m.advance(ctx, def, instance)
+}
+
+// inProgress reports whether an unfinished instance of the saga exists for key
+func (m *Manager) inProgress(ctx context.Context, def Definition, key string) (bool, error) {
+	instance, err := m.store.FindByCorrelationKey(ctx, def.Name(), key)
+	if errors.Is(err, adapter.ErrEntityNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !instance.Done(), nil
+}
+
+func (m *Manager) complete(ctx context.Context, def Definition, b binding, event any) error {
+	instance, ok, err := m.waitingInstance(ctx, def, b, event)
+	if err != nil || !ok {
+		return err
+	}
+
+	data, err := b.apply(ctx, event, instance.Data)
+	if err != nil {
+		return err
+	}
+
+	instance.Data = data
+	instance.CurrentStep++
+	instance.Status = SagaStatusRunning
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	return m.advance(ctx, def, instance)
+}
+
+func (m *Manager) fail(ctx context.Context, def Definition, b binding, event any) error {
+	instance, ok, err := m.waitingInstance(ctx, def, b, event)
+	if err != nil || !ok {
+		return err
+	}
+
+	reason := "step " + b.step + " failed"
+	if b.reason != nil {
+		reason += ": " + b.reason(event)
+	}
+
+	// The work of the failed step did not happen, so only the steps before it are undone
+	instance.ExecutedSteps = instance.CurrentStep
+	return m.compensate(ctx, def, instance, errors.New(reason))
+}
+
+// waitingInstance returns the instance the event is for, if it is waiting on the bound step.
+// Events for unknown, finished or already advanced sagas are ignored, so redelivered
+// events are harmless.
+func (m *Manager) waitingInstance(ctx context.Context, def Definition, b binding, event any) (*Instance, bool, error) {
+	key := b.key(event)
+	instance, err := m.store.FindByCorrelationKey(ctx, def.Name(), key)
+	if errors.Is(err, adapter.ErrEntityNotFound) {
+		logging.Debug("Saga not found for event").
+			WithString("saga", def.Name()).
+			WithString("correlation_key", key).
+			Log()
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if instance.Status != SagaStatusWaiting || instance.CurrentStepName != b.step {
+		logging.Debug("Saga is not waiting for event").
+			WithString("saga", def.Name()).
+			WithString("correlation_key", key).
+			WithString("status", string(instance.Status)).
+			WithString("current_step", instance.CurrentStepName).
+			Log()
+		return nil, false, nil
+	}
+
+	return instance, true, nil
+}
+
+// advance runs the steps from the current one until the saga completes, a step waits for
+// its event or a step fails, in which case the saga is compensated
+func (m *Manager) advance(ctx context.Context, def Definition, instance *Instance) error {
+	steps := def.steps()
+
+	for instance.CurrentStep < len(steps) {
+		step := steps[instance.CurrentStep]
+		instance.CurrentStepName = step.name
+
+		if step.waits {
+			return m.wait(ctx, def, instance, step)
+		}
+
+		// The step is saved as executed before its action runs: a saga resumed after a
+		// crash runs the action again, and compensates it if the saga fails later
+		instance.ExecutedSteps = instance.CurrentStep + 1
+		if err := m.save(ctx, instance); err != nil {
+			return err
+		}
+
+		data, err := def.runAction(m.stepContext(ctx), instance.CurrentStep, instance.Data)
+		if err != nil {
+			// The action failed, so only the steps before it are undone
+			instance.ExecutedSteps = instance.CurrentStep
+			return m.compensate(ctx, def, instance, fmt.Errorf("step %s failed: %w", step.name, err))
+		}
+		instance.Data = data
+
+		instance.CurrentStep++
+		if err := m.save(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	instance.Status = SagaStatusCompleted
+	instance.CurrentStepName = ""
+	instance.DeadlineAt = nil
+	instance.FinishedAt = &now
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	logging.Info("Saga completed").
+		WithString("saga", def.Name()).
+		WithString("correlation_key", instance.CorrelationKey).
+		WithAny("saga_id", instance.ID).
+		Log()
+	return nil
+}
+
+// wait saves that the saga waits for the event completing step and then runs the step
+// action. The event may be handled before the action returns, so the saga must already be
+// waiting for it.
+func (m *Manager) wait(ctx context.Context, def Definition, instance *Instance, step stepInfo) error {
+	instance.Status = SagaStatusWaiting
+	instance.ExecutedSteps = instance.CurrentStep + 1
+	instance.DeadlineAt = nil
+	if timeout := m.stepTimeout(step); timeout > 0 {
+		deadline := time.Now().Add(timeout)
+		instance.DeadlineAt = &deadline
+	}
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	data, err := def.runAction(m.stepContext(ctx), instance.CurrentStep, instance.Data)
+	if err != nil {
+		// The requested work did not happen, so only the steps before it are undone
+		instance.ExecutedSteps = instance.CurrentStep
+		return m.compensate(ctx, def, instance, fmt.Errorf("step %s failed: %w", step.name, err))
+	}
+	if data == instance.Data {
+		return nil
+	}
+
+	instance.Data = data
+	err = m.save(ctx, instance)
+	if errors.Is(err, ErrConcurrentUpdate) {
+		// The event completing the step was handled first; it saw the data before the action
+		logging.Warn("Saga data changed by a step action was not saved").
+			WithString("saga", def.Name()).
+			WithAny("saga_id", instance.ID).
+			WithString("step", step.name).
+			Log()
+		return nil
+	}
+	return err
+}
+
+// compensate starts undoing the executed steps of the saga because of cause
+func (m *Manager) compensate(ctx context.Context, def Definition, instance *Instance, cause error) error {
+	causeMsg := cause.Error()
+	instance.Status = SagaStatusCompensating
+	instance.LastError = &causeMsg
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	logging.Warn("Compensating saga").
+		WithString("saga", def.Name()).
+		WithString("correlation_key", instance.CorrelationKey).
+		WithAny("saga_id", instance.ID).
+		WithError(cause).
+		Log()
+
+	return m.runCompensations(ctx, def, instance, causeMsg)
+}
+
+// runCompensations runs the compensations of the executed steps in reverse order. A failing
+// compensation stops the saga in the failed status for manual attention.
+func (m *Manager) runCompensations(ctx context.Context, def Definition, instance *Instance, causeMsg string) error {
+	steps := def.steps()
+
+	for instance.ExecutedSteps > 0 {
+		i := instance.ExecutedSteps - 1
+		instance.CurrentStepName = steps[i].name
+
+		data, err := def.runCompensation(m.stepContext(ctx), i, instance.Data)
+		if err != nil {
+			now := time.Now()
+			errMsg := fmt.Sprintf("compensation of step %s failed: %v (compensating: %s)", steps[i].name, err, causeMsg)
+			instance.Status = SagaStatusFailed
+			instance.LastError = &errMsg
+			instance.DeadlineAt = nil
+			instance.FinishedAt = &now
+
+			logging.Error("Saga compensation failed").
+				WithString("saga", def.Name()).
+				WithString("correlation_key", instance.CorrelationKey).
+				WithAny("saga_id", instance.ID).
+				WithString("step", steps[i].name).
+				WithError(err).
+				Log()
+			return m.save(ctx, instance)
+		}
+
+		instance.Data = data
+		instance.ExecutedSteps = i
+		if err := m.save(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	instance.Status = SagaStatusCompensated
+	instance.CurrentStepName = ""
+	instance.DeadlineAt = nil
+	instance.FinishedAt = &now
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	logging.Info("Saga compensated").
+		WithString("saga", def.Name()).
+		WithString("correlation_key", instance.CorrelationKey).
+		WithAny("saga_id", instance.ID).
+		Log()
+	return nil
+}
+
+// stepContext returns the context step actions and compensations run with, through which
+// Dispatch reaches the message bus. Commands sent by steps are dispatched as the system
+// principal, since no caller is waiting on them.
+func (m *Manager) stepContext(ctx context.Context) context.Context {
+	ctx = authorization.WithSystemPrincipal(ctx)
+	return context.WithValue(ctx, busKey{}, m.bus)
+}
+
+func (m *Manager) stepTimeout(step stepInfo) time.Duration {
+	if step.timeout > 0 {
+		return step.timeout
+	}
+	return m.config.DefaultStepTimeout
+}
+
+// save persists the instance through the unit of work. Running and compensating instances
+// get a new recovery deadline, so the timeout checker resumes them only once they stop
+// making progress.
+func (m *Manager) save(ctx context.Context, instance *Instance) error {
+	if instance.Status == SagaStatusRunning || instance.Status == SagaStatusCompensating {
+		deadline := time.Now().Add(m.config.RecoveryTimeout)
+		instance.DeadlineAt = &deadline
+	}
+
+	return m.bus.Uow().Do(ctx, func(ctx context.Context) error {
+		return m.store.Update(ctx, instance)
+	})
+}
+
+// Start compensates sagas whose waiting step timed out and resumes stalled sagas, until
+// Shutdown is called
+func (m *Manager) Start() error {
+	defer close(m.doneChan)
+
+	ticker := time.NewTicker(m.config.TimeoutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			logging.Info("Saga manager stopped").Log()
+			return nil
+		case <-ticker.C:
+			m.checkTimeouts()
+		}
+	}
+}
+
+func (m *Manager) checkTimeouts() {
+	ctx := context.Background()
+
+	instances, err := m.store.ListTimedOut(ctx, time.Now(), m.config.TimeoutBatchSize)
+	if err != nil {
+		logging.Error("Failed to list timed out sagas").WithError(err).Log()
+		return
+	}
+
+	for _, instance := range instances {
+		m.mu.RLock()
+		def, ok := m.sagas[instance.SagaType]
+		m.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if err := m.handleTimedOut(ctx, def, instance); err != nil && !errors.Is(err, ErrConcurrentUpdate) {
+			logging.Error("Failed to recover timed out saga").
+				WithString("saga", def.Name()).
+				WithAny("saga_id", instance.ID).
+				WithString("status", string(instance.Status)).
+				WithError(err).
+				Log()
+		}
+	}
+}
+
+// handleTimedOut compensates a saga whose waiting step timed out, or resumes a running or
+// compensating saga that was not saved within the recovery timeout
+func (m *Manager) handleTimedOut(ctx context.Context, def Definition, instance *Instance) error {
+	if instance.Status == SagaStatusWaiting {
+		return m.compensate(ctx, def, instance, fmt.Errorf("step %s timed out", instance.CurrentStepName))
+	}
+
+	// Saving first claims the instance: another manager resuming it concurrently gets
+	// ErrConcurrentUpdate
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	logging.Warn("Resuming stalled saga").
+		WithString("saga", def.Name()).
+		WithString("correlation_key", instance.CorrelationKey).
+		WithAny("saga_id", instance.ID).
+		WithString("status", string(instance.Status)).
+		WithString("current_step", instance.CurrentStepName).
+		Log()
+
+	if instance.Status == SagaStatusRunning {
+		return m.advance(ctx, def, instance)
+	}
+
+	causeMsg := "saga stalled while compensating"
+	if instance.LastError != nil {
+		causeMsg = *instance.LastError
+	}
+	return m.runCompensations(ctx, def, instance, causeMsg)
+}
+
+// Shutdown stops the timeout checks
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+
+	select {
+	case <-m.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Name returns the name of the service for logging purposes
+func (m *Manager) Name() string {
+	return "saga-manager"
+}
@@ -0,0 +1,159 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+)
+
+// ErrConcurrentUpdate is returned when a saga instance was changed since it was loaded
+var ErrConcurrentUpdate = errors.New("saga instance was updated concurrently")
+
+// ListFilter narrows the saga instances returned by Store.List
+type ListFilter struct {
+	SagaType string     // Only instances of this saga, if set
+	Status   SagaStatus // Only instances with this status, if set
+	Limit    int        // Maximum number of results (default 50)
+	Offset   int
+}
+
+// Store defines the interface for saga instance persistence
+type Store interface {
+	// Create stores a new instance; it fails when an unfinished instance of the saga
+	// exists for the same correlation key
+	Create(ctx context.Context, instance *Instance) error
+	Get(ctx context.Context, id SagaID) (*Instance, error)
+	// FindByCorrelationKey returns the most recent instance of the saga for key
+	FindByCorrelationKey(ctx context.Context, sagaType string, key string) (*Instance, error)
+	// Update saves instance if it was not changed since it was loaded, and bumps its version
+	Update(ctx context.Context, instance *Instance) error
+	List(ctx context.Context, filter ListFilter) ([]*Instance, error)
+	// ListTimedOut returns unfinished instances whose deadline passed: waiting instances whose
+	// step timed out, and running or compensating instances that stopped making progress
+	ListTimedOut(ctx context.Context, now time.Time, limit int) ([]*Instance, error)
+}
+
+// GormStore is a GORM implementation of the saga store. It uses the session of the unit of
+// work, so saga state is written in the transaction of the surrounding uow.Do, if any.
+type GormStore struct {
+	uow       adapter.UnitOfWork
+	tableName string
+}
+
+// NewGormStore creates a new GORM-based saga store
+// If tableName is empty, it will use the default table name from Instance.TableName()
+func NewGormStore(uow adapter.UnitOfWork, tableName string) Store {
+	return &GormStore{
+		uow:       uow,
+		tableName: tableName,
+	}
+}
+
+func (s *GormStore) Model(ctx context.Context) *gorm.DB {
+	model := s.uow.GetSession(ctx).WithContext(ctx).Model(&Instance{})
+	if s.tableName != "" {
+		model = model.Table(s.tableName)
+	}
+	return model
+}
+
+func (s *GormStore) Create(ctx context.Context, instance *Instance) error {
+	instance.ActiveKey = activeKey(instance)
+	return s.Model(ctx).Create(instance).Error
+}
+
+func (s *GormStore) Get(ctx context.Context, id SagaID) (*Instance, error) {
+	return s.first(s.Model(ctx).Where("id = ?", uint64(id)))
+}
+
+func (s *GormStore) FindByCorrelationKey(ctx context.Context, sagaType string, key string) (*Instance, error) {
+	return s.first(s.Model(ctx).Where("saga_type = ? AND correlation_key = ?", sagaType, key).Order("id DESC"))
+}
+
+func (s *GormStore) first(query *gorm.DB) (*Instance, error) {
+	var instance Instance
+	err := query.First(&instance).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, adapter.ErrEntityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+func (s *GormStore) Update(ctx context.Context, instance *Instance) error {
+	now := time.Now()
+	result := s.Model(ctx).
+		Where("id = ? AND version = ?", uint64(instance.ID), instance.Version).
+		Updates(map[string]interface{}{
+			"status":            instance.Status,
+			"current_step":      instance.CurrentStep,
+			"current_step_name": instance.CurrentStepName,
+			"executed_steps":    instance.ExecutedSteps,
+			"data":              instance.Data,
+			"last_error":        instance.LastError,
+			"deadline_at":       instance.DeadlineAt,
+			"finished_at":       instance.FinishedAt,
+			"active_key":        activeKey(instance),
+			"version":           instance.Version + 1,
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConcurrentUpdate
+	}
+
+	instance.ActiveKey = activeKey(instance)
+	instance.Version++
+	instance.UpdatedAt = now
+	return nil
+}
+
+// activeKey returns the value of the active_key column, which is unique per saga type and
+// NULL once the saga finished, so a key can start a new instance after the previous one
+func activeKey(instance *Instance) *string {
+	if instance.Done() {
+		return nil
+	}
+	key := instance.CorrelationKey
+	return &key
+}
+
+func (s *GormStore) List(ctx context.Context, filter ListFilter) ([]*Instance, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+
+	query := s.Model(ctx)
+	if filter.SagaType != "" {
+		query = query.Where("saga_type = ?", filter.SagaType)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var instances []*Instance
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&instances).Error
+	return instances, err
+}
+
+func (s *GormStore) ListTimedOut(ctx context.Context, now time.Time, limit int) ([]*Instance, error) {
+	var instances []*Instance
+	err := s.Model(ctx).
+		Where("status IN ? AND deadline_at <= ?", []SagaStatus{SagaStatusRunning, SagaStatusWaiting, SagaStatusCompensating}, now).
+		Order("deadline_at ASC").
+		Limit(limit).
+		Find(&instances).Error
+	return instances, err
+}
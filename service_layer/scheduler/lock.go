@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/redisx"
+)
+
+// Locker acquires locks shared by every replica running the scheduler
+type Locker interface {
+	// Acquire takes the lock named key for at most ttl. It returns false when another
+	// owner holds it. The returned release function gives the lock back early.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (release func(context.Context) error, ok bool, err error)
+}
+
+// RedisLocker is a Locker based on Redis SET NX with expiry
+type RedisLocker struct {
+	conn redisx.LockConnection
+}
+
+// NewRedisLocker creates a new Redis-based locker
+func NewRedisLocker(conn redisx.LockConnection) Locker {
+	return &RedisLocker{conn: conn}
+}
+
+func (l *RedisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	token := uuid.New().String()
+	ok, err := l.conn.SetValueIfAbsent(ctx, key, token, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	release := func(ctx context.Context) error {
+		_, err := l.conn.DeleteKeyIfValue(ctx, key, token)
+		return err
+	}
+	return release, true, nil
+}
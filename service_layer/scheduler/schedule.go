@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule returns the next time a job runs after the given time
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// Cron parses a cron expression: five fields (minute to day of week), six with a leading
+// seconds field, or a descriptor such as "@daily" or "@every 5m". Expressions may start with
+// "CRON_TZ=<zone>" to be evaluated in a time zone other than the scheduler's.
+func Cron(expr string) (Schedule, error) {
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return schedule, nil
+}
+
+// MustCron is like Cron but panics when the expression is invalid
+func MustCron(expr string) Schedule {
+	schedule, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+// intervalSchedule runs at fixed intervals aligned to multiples of the interval
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule running every interval. Runs are aligned to multiples of the
+// interval since the Unix epoch, so replicas agree on the run times.
+func Every(interval time.Duration) Schedule {
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	// time.Truncate aligns to the zero time rather than the Unix epoch
+	interval := s.interval.Nanoseconds()
+	next := (t.UnixNano()/interval + 1) * interval
+	return time.Unix(0, next).In(t.Location())
+}
@@ -0,0 +1,297 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/authorization"
+)
+
+// CommandBus dispatches the scheduled commands; it is implemented by messagebus.MessageBus
+type CommandBus interface {
+	Handle(ctx context.Context, cmd any) error
+}
+
+// Job is a command dispatched on a schedule
+type Job struct {
+	Name           string        // Unique name, used in logs and lock keys
+	Schedule       Schedule      // When the job runs (see Cron and Every)
+	Command        func() any    // Builds the command dispatched on each run
+	Timeout        time.Duration // Maximum duration of one run; 0 uses the scheduler default
+	SingleInstance bool          // Run on only one replica per scheduled time, using the Locker
+}
+
+// Config holds configuration for the scheduler
+type Config struct {
+	Location       *time.Location // Time zone schedules are evaluated in
+	DefaultTimeout time.Duration  // Maximum duration of runs of jobs without a timeout
+	Locker         Locker         // Required by single-instance jobs
+	LockPrefix     string         // Prefix of lock keys
+}
+
+// DefaultConfig returns default configuration for the scheduler
+func DefaultConfig() Config {
+	return Config{
+		Location:       time.UTC,
+		DefaultTimeout: 5 * time.Minute,
+		LockPrefix:     "scheduler",
+	}
+}
+
+// Scheduler dispatches registered commands through the message bus on their schedules.
+// Runs of the same job never overlap: a run that is still in progress when the job is due
+// again makes the scheduler skip that time. It implements service_host.Service.
+type Scheduler struct {
+	bus    CommandBus
+	config Config
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	started bool
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	doneChan chan struct{}
+}
+
+// New creates a new scheduler
+func New(bus CommandBus, cfg Config) *Scheduler {
+	defaults := DefaultConfig()
+	if cfg.Location == nil {
+		cfg.Location = defaults.Location
+	}
+	if cfg.DefaultTimeout <= 0 {
+		cfg.DefaultTimeout = defaults.DefaultTimeout
+	}
+	if cfg.LockPrefix == "" {
+		cfg.LockPrefix = defaults.LockPrefix
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		bus:      bus,
+		config:   cfg,
+		jobs:     make(map[string]*Job),
+		ctx:      ctx,
+		cancel:   cancel,
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Register adds jobs to the scheduler. Jobs must be registered before Start.
+func (s *Scheduler) Register(jobs ...Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("scheduler already started")
+	}
+
+	for _, job := range jobs {
+		if job.Name == "" {
+			return fmt.Errorf("scheduled job name is required")
+		}
+		if job.Schedule == nil || job.Command == nil {
+			return fmt.Errorf("scheduled job %s requires a schedule and a command", job.Name)
+		}
+		if job.SingleInstance && s.config.Locker == nil {
+			return fmt.Errorf("scheduled job %s is single-instance but no locker is configured", job.Name)
+		}
+		if _, exists := s.jobs[job.Name]; exists {
+			return fmt.Errorf("scheduled job %s already registered", job.Name)
+		}
+		if job.Timeout <= 0 {
+			job.Timeout = s.config.DefaultTimeout
+		}
+
+		s.jobs[job.Name] = &job
+	}
+
+	return nil
+}
+
+// Start runs the registered jobs on their schedules until Shutdown is called
+func (s *Scheduler) Start() error {
+	defer close(s.doneChan)
+
+	s.mu.Lock()
+	s.started = true
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	logging.Info("Scheduler started").
+		WithInt("jobs", len(jobs)).
+		Log()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go func(job *Job) {
+			defer s.wg.Done()
+			s.loop(job)
+		}(job)
+	}
+
+	<-s.ctx.Done()
+	s.wg.Wait()
+
+	logging.Info("Scheduler stopped").Log()
+	return nil
+}
+
+// loop waits for each scheduled time of job and runs it. Times that pass while a run is
+// in progress are skipped, so runs never overlap.
+func (s *Scheduler) loop(job *Job) {
+	next := job.Schedule.Next(time.Now().In(s.config.Location))
+	for {
+		if next.IsZero() {
+			logging.Warn("Scheduled job has no next run time").
+				WithString("job", job.Name).
+				Log()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(job, next)
+
+		now := time.Now().In(s.config.Location)
+		following := job.Schedule.Next(next)
+		if !following.IsZero() && following.Before(now) {
+			logging.Warn("Scheduled job run overran its schedule; skipping missed runs").
+				WithString("job", job.Name).
+				WithAny("scheduled_at", next).
+				Log()
+			following = job.Schedule.Next(now)
+		}
+		next = following
+	}
+}
+
+// run dispatches the command of job for the given scheduled time. A run in progress is
+// allowed to finish during shutdown, bounded by the job timeout.
+func (s *Scheduler) run(job *Job, scheduledAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), job.Timeout)
+	defer cancel()
+
+	if job.SingleInstance {
+		release, ok := s.lock(ctx, job, scheduledAt)
+		if !ok {
+			return
+		}
+		defer func() {
+			if err := release(context.Background()); err != nil {
+				logging.Warn("Failed to release scheduled job lock").
+					WithString("job", job.Name).
+					WithError(err).
+					Log()
+			}
+		}()
+	}
+
+	cmd := job.Command()
+	start := time.Now()
+	err := s.dispatch(ctx, cmd)
+	duration := time.Since(start)
+
+	if err != nil {
+		logging.Error("Scheduled job failed").
+			WithString("job", job.Name).
+			WithAny("command_name", reflect.TypeOf(cmd).String()).
+			WithAny("scheduled_at", scheduledAt).
+			WithAny("duration", duration).
+			WithError(err).
+			Log()
+		return
+	}
+
+	logging.Info("Scheduled job completed").
+		WithString("job", job.Name).
+		WithAny("command_name", reflect.TypeOf(cmd).String()).
+		WithAny("scheduled_at", scheduledAt).
+		WithAny("duration", duration).
+		Log()
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, cmd any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scheduled command panicked: %v", r)
+		}
+	}()
+	return s.bus.Handle(authorization.WithSystemPrincipal(ctx), cmd)
+}
+
+// lock claims the scheduled time for this replica and takes the job's run lock.
+// The claim is kept until it expires so replicas with skewed clocks do not run the same
+// time twice; the run lock is released when the run ends and keeps runs from overlapping
+// across replicas.
+func (s *Scheduler) lock(ctx context.Context, job *Job, scheduledAt time.Time) (func(context.Context) error, bool) {
+	key := s.config.LockPrefix + ":" + job.Name
+	tickKey := key + ":" + strconv.FormatInt(scheduledAt.Unix(), 10)
+
+	if _, ok, err := s.config.Locker.Acquire(ctx, tickKey, job.Timeout); err != nil || !ok {
+		s.logLockNotAcquired(job, scheduledAt, err)
+		return nil, false
+	}
+
+	release, ok, err := s.config.Locker.Acquire(ctx, key, job.Timeout)
+	if err != nil || !ok {
+		s.logLockNotAcquired(job, scheduledAt, err)
+		return nil, false
+	}
+	return release, true
+}
+
+func (s *Scheduler) logLockNotAcquired(job *Job, scheduledAt time.Time, err error) {
+	if err != nil {
+		logging.Error("Failed to acquire scheduled job lock").
+			WithString("job", job.Name).
+			WithAny("scheduled_at", scheduledAt).
+			WithError(err).
+			Log()
+		return
+	}
+	logging.Debug("Scheduled job is running on another instance").
+		WithString("job", job.Name).
+		WithAny("scheduled_at", scheduledAt).
+		Log()
+}
+
+// Shutdown stops scheduling new runs and waits for runs in progress to finish
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-s.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Name returns the name of the service for logging purposes
+func (s *Scheduler) Name() string {
+	return "scheduler"
+}
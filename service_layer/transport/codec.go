@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/adapter"
+	apperrors "github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/service_layer/eventschema"
+)
+
+// Codec serializes events together with their envelope for a transport
+type Codec interface {
+	// Encode serializes event and its envelope
+	Encode(envelope adapter.EventEnvelope, event any) ([]byte, error)
+	// Decode restores the envelope and the event from data. newEvent returns a pointer to
+	// a new value of the Go type of an event name, and false for unknown events.
+	Decode(data []byte, newEvent func(name string) (any, bool)) (adapter.EventEnvelope, any, error)
+}
+
+// jsonMessage is the wire format of JSONCodec: the envelope fields next to the event payload,
+// the same shape the outbox processor publishes
+type jsonMessage struct {
+	adapter.EventEnvelope
+	Payload json.RawMessage `json:"payload"`
+}
+
+type jsonCodec struct {
+	registry *eventschema.Registry
+}
+
+// NewJSONCodec returns a codec encoding events as JSON. Payloads written with an older schema
+// version are upcast to the current one through the default eventschema registry.
+func NewJSONCodec() Codec {
+	return NewJSONCodecWithRegistry(eventschema.Default())
+}
+
+// NewJSONCodecWithRegistry returns a JSON codec upcasting payloads through registry
+func NewJSONCodecWithRegistry(registry *eventschema.Registry) Codec {
+	return &jsonCodec{registry: registry}
+}
+
+func (c *jsonCodec) Encode(envelope adapter.EventEnvelope, event any) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", envelope.EventType, err)
+	}
+
+	if envelope.EventType == "" {
+		envelope.EventType = c.registry.Name(event)
+	}
+	if envelope.EventVersion == 0 {
+		envelope.EventVersion = c.registry.Version(event)
+	}
+
+	return json.Marshal(jsonMessage{EventEnvelope: envelope, Payload: payload})
+}
+
+func (c *jsonCodec) Decode(data []byte, newEvent func(name string) (any, bool)) (adapter.EventEnvelope, any, error) {
+	var message jsonMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return adapter.EventEnvelope{}, nil, apperrors.Validation("", fmt.Sprintf("failed to decode message: %v", err))
+	}
+
+	envelope := message.EventEnvelope
+	if envelope.EventType == "" {
+		return envelope, nil, apperrors.Validation("", "message has no event type")
+	}
+
+	event, ok := newEvent(envelope.EventType)
+	if !ok {
+		return envelope, nil, apperrors.NotFound("", fmt.Sprintf("no Go type registered for event type %s", envelope.EventType))
+	}
+	if err := c.registry.DecodeInto(envelope.EventType, envelope.EventVersion, message.Payload, event); err != nil {
+		return envelope, nil, apperrors.Validation("", fmt.Sprintf("failed to decode payload of %s: %v", envelope.EventType, err))
+	}
+
+	return envelope, event, nil
+}
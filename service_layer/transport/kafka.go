@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// KafkaProducer sends encoded messages to Kafka, as implemented by kafka.Service. Producers
+// that implement io.Closer are closed with the transport.
+type KafkaProducer interface {
+	SendRawMessage(topic string, key string, value []byte) error
+}
+
+// KafkaConsumerGroup is a member of a Kafka consumer group, as implemented by
+// sarama.ConsumerGroup (see kafka.Service.NewConsumerGroup)
+type KafkaConsumerGroup interface {
+	Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error
+	Close() error
+}
+
+// KafkaGroupFactory creates a consumer group member. Subscribe creates one per topic and
+// closes it when the subscription ends.
+type KafkaGroupFactory func() (KafkaConsumerGroup, error)
+
+// KafkaTransport publishes messages to Kafka topics. Messages are keyed by their partition
+// key so related events stay in order. Subscriptions consume as members of a consumer group:
+// replicas of a service sharing a group ID split the messages of a topic, each handled by one
+// of them, while every group receives every message. Offsets are committed once the handler
+// returns, so messages published while no member was running are delivered when one starts,
+// and a message whose handling was interrupted by a crash is delivered again.
+type KafkaTransport struct {
+	producer KafkaProducer
+	newGroup KafkaGroupFactory
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// NewKafkaTransport creates a new Kafka transport. newGroup may be nil for publish-only use.
+func NewKafkaTransport(producer KafkaProducer, newGroup KafkaGroupFactory) *KafkaTransport {
+	return &KafkaTransport{
+		producer: producer,
+		newGroup: newGroup,
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// Publish sends msg to its Kafka topic
+func (t *KafkaTransport) Publish(ctx context.Context, msg Message) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := t.producer.SendRawMessage(msg.Topic, msg.Key, msg.Body); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe handles the messages of topic assigned to this consumer group member until ctx
+// is done or the transport is closed. Partitions are handled concurrently, each one in order.
+func (t *KafkaTransport) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	if t.newGroup == nil {
+		return fmt.Errorf("kafka transport has no consumer group to subscribe to %s", topic)
+	}
+
+	group, err := t.newGroup()
+	if err != nil {
+		return fmt.Errorf("failed to join consumer group for %s: %w", topic, err)
+	}
+	defer group.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-t.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	claimHandler := &kafkaClaimHandler{transport: t, topic: topic, handler: handler}
+	for ctx.Err() == nil {
+		// Consume returns when the group rebalances and is called again to rejoin it
+		err := group.Consume(ctx, []string{topic}, claimHandler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return nil
+		}
+		if err != nil {
+			logging.Error("Kafka consumer group error").
+				WithString("topic", topic).
+				WithError(err).
+				Log()
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	return nil
+}
+
+// kafkaClaimHandler handles the partitions of a topic claimed by a consumer group member
+type kafkaClaimHandler struct {
+	transport *KafkaTransport
+	topic     string
+	handler   Handler
+}
+
+func (h *kafkaClaimHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaClaimHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaClaimHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case kafkaMessage, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			msg := Message{Topic: h.topic, Key: string(kafkaMessage.Key), Body: kafkaMessage.Value}
+			if err := h.handler(ctx, msg); err != nil {
+				logging.Error("Failed to handle transport message").
+					WithString("transport", h.transport.Name()).
+					WithString("topic", h.topic).
+					WithInt("partition", int(kafkaMessage.Partition)).
+					WithInt64("offset", kafkaMessage.Offset).
+					WithError(err).
+					Log()
+			}
+			// Failed messages were retried and dead-lettered by the message bus, so the offset
+			// moves past them too
+			session.MarkMessage(kafkaMessage, "")
+		}
+	}
+}
+
+// Close stops every subscription and closes the producer
+func (t *KafkaTransport) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.closeCh)
+		if closer, ok := t.producer.(io.Closer); ok {
+			err = closer.Close()
+		}
+	})
+	return err
+}
+
+// Name returns the name of the transport for logging purposes
+func (t *KafkaTransport) Name() string {
+	return "kafka"
+}
+
+func (t *KafkaTransport) isClosed() bool {
+	select {
+	case <-t.closeCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+)
+
+// MemoryConfig holds configuration for the in-memory transport
+type MemoryConfig struct {
+	BufferSize int // Messages buffered per subscriber before Publish blocks
+}
+
+// DefaultMemoryConfig returns default configuration for the in-memory transport
+func DefaultMemoryConfig() MemoryConfig {
+	return MemoryConfig{
+		BufferSize: 100,
+	}
+}
+
+// MemoryTransport delivers messages to subscribers in the same process. It goes through the
+// codec like the other transports, so it is a drop-in replacement for them in tests and
+// single-process deployments.
+type MemoryTransport struct {
+	config MemoryConfig
+
+	mu          sync.RWMutex
+	subscribers map[string][]chan Message
+	closed      bool
+	closeCh     chan struct{}
+}
+
+// NewMemoryTransport creates a new in-memory transport
+func NewMemoryTransport(cfg MemoryConfig) *MemoryTransport {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultMemoryConfig().BufferSize
+	}
+
+	return &MemoryTransport{
+		config:      cfg,
+		subscribers: make(map[string][]chan Message),
+		closeCh:     make(chan struct{}),
+	}
+}
+
+// Publish delivers msg to every current subscriber of its topic, waiting for buffer space
+func (t *MemoryTransport) Publish(ctx context.Context, msg Message) error {
+	// The lock is not held while waiting, so subscribers publishing from their handler,
+	// unsubscribing or closing the transport are not blocked
+	t.mu.RLock()
+	if t.closed {
+		t.mu.RUnlock()
+		return ErrClosed
+	}
+	subscribers := append([]chan Message(nil), t.subscribers[msg.Topic]...)
+	t.mu.RUnlock()
+
+	for _, ch := range subscribers {
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.closeCh:
+			return ErrClosed
+		}
+	}
+	return nil
+}
+
+// Subscribe handles the messages published to topic until ctx is done or the transport is closed
+func (t *MemoryTransport) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	ch := make(chan Message, t.config.BufferSize)
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	t.subscribers[topic] = append(t.subscribers[topic], ch)
+	t.mu.Unlock()
+
+	defer t.unsubscribe(topic, ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.closeCh:
+			return nil
+		case msg := <-ch:
+			if err := handler(ctx, msg); err != nil {
+				logging.Error("Failed to handle transport message").
+					WithString("transport", t.Name()).
+					WithString("topic", topic).
+					WithError(err).
+					Log()
+			}
+		}
+	}
+}
+
+func (t *MemoryTransport) unsubscribe(topic string, ch chan Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	subscribers := t.subscribers[topic]
+	for i, subscriber := range subscribers {
+		if subscriber == ch {
+			t.subscribers[topic] = append(subscribers[:i:i], subscribers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Close stops every subscription; messages still buffered are not delivered
+func (t *MemoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.closed {
+		t.closed = true
+		close(t.closeCh)
+	}
+	return nil
+}
+
+// Name returns the name of the transport for logging purposes
+func (t *MemoryTransport) Name() string {
+	return "memory"
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/logging"
+	"github.com/ali-mahdavi-dev/shikposh-framework/infrastructure/redisx"
+)
+
+// RedisConfig holds configuration for the Redis pub/sub transport
+type RedisConfig struct {
+	Prefix string // Prefix of the channel names topics are published on
+}
+
+// DefaultRedisConfig returns default configuration for the Redis pub/sub transport
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Prefix: "events",
+	}
+}
+
+// RedisTransport publishes messages on Redis pub/sub channels. Redis pub/sub is fire and
+// forget: messages published while a subscriber is disconnected are lost to it, and
+// partition keys are ignored since each channel is delivered in publish order.
+type RedisTransport struct {
+	conn   redisx.PubSubConnection
+	config RedisConfig
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// NewRedisTransport creates a new Redis pub/sub transport
+func NewRedisTransport(conn redisx.PubSubConnection, cfg RedisConfig) *RedisTransport {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultRedisConfig().Prefix
+	}
+
+	return &RedisTransport{
+		conn:    conn,
+		config:  cfg,
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Publish publishes msg on the channel of its topic
+func (t *RedisTransport) Publish(ctx context.Context, msg Message) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	if err := t.conn.Publish(ctx, t.channel(msg.Topic), msg.Body); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe handles the messages published on the channel of topic until ctx is done or the
+// transport is closed
+func (t *RedisTransport) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+
+	channel := t.channel(topic)
+	pubsub := t.conn.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed so messages published afterwards are received
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	logging.Info("Subscribed to Redis channel").
+		WithString("channel", channel).
+		Log()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.closeCh:
+			return nil
+		case redisMessage, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			msg := Message{Topic: topic, Body: []byte(redisMessage.Payload)}
+			if err := handler(ctx, msg); err != nil {
+				logging.Error("Failed to handle transport message").
+					WithString("transport", t.Name()).
+					WithString("topic", topic).
+					WithError(err).
+					Log()
+			}
+		}
+	}
+}
+
+// Close stops every subscription. The Redis connection is owned by the caller and stays open.
+func (t *RedisTransport) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.closeCh)
+	})
+	return nil
+}
+
+// Name returns the name of the transport for logging purposes
+func (t *RedisTransport) Name() string {
+	return "redis"
+}
+
+func (t *RedisTransport) channel(topic string) string {
+	return t.config.Prefix + ":" + topic
+}
+
+func (t *RedisTransport) isClosed() bool {
+	select {
+	case <-t.closeCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed is returned when publishing to or subscribing on a closed transport
+var ErrClosed = errors.New("transport is closed")
+
+// Message is an encoded event travelling through a transport
+type Message struct {
+	Topic string // Topic (or channel) the message is published to
+	Key   string // Partition key keeping related events in order, where the transport supports it
+	Body  []byte // Event and its envelope encoded by a Codec
+}
+
+// Handler handles a message received from a subscription. Transports do not redeliver
+// messages whose handler failed: retries and dead-lettering are done by the message bus,
+// so they behave the same whichever transport delivered the event.
+type Handler func(ctx context.Context, msg Message) error
+
+// Transport publishes encoded events to topics and delivers them to the subscribers of
+// those topics, possibly in other processes. Every subscriber of a topic receives every
+// message published to it after it subscribed, except with consumer groups (KafkaTransport),
+// where each group receives it once.
+type Transport interface {
+	// Publish sends msg to the subscribers of msg.Topic
+	Publish(ctx context.Context, msg Message) error
+	// Subscribe calls handler for every message published to topic, one at a time, until
+	// ctx is done or the transport is closed
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+	// Close releases the transport; running subscriptions return
+	Close() error
+	// Name returns the name of the transport for logging purposes
+	Name() string
+}
@@ -18,6 +18,28 @@ func (s *AndSpecification[T]) IsSatisfiedBy(entity T) bool {
 	return s.left.IsSatisfiedBy(entity) && s.right.IsSatisfiedBy(entity)
 }
 
+// Left returns the left operand
+func (s *AndSpecification[T]) Left() Specification[T] {
+	return s.left
+}
+
+// Right returns the right operand
+func (s *AndSpecification[T]) Right() Specification[T] {
+	return s.right
+}
+
+// Explain evaluates both operands without short-circuiting so every violation is reported
+func (s *AndSpecification[T]) Explain(entity T) Result {
+	left := Explain(s.left, entity)
+	right := Explain(s.right, entity)
+	return Result{
+		Name:      string(OperatorAnd),
+		Operator:  OperatorAnd,
+		Satisfied: left.Satisfied && right.Satisfied,
+		Children:  []Result{left, right},
+	}
+}
+
 // OrSpecification combines two specifications with logical OR
 type OrSpecification[T any] struct {
 	left  Specification[T]
@@ -36,6 +58,28 @@ func (s *OrSpecification[T]) IsSatisfiedBy(entity T) bool {
 	return s.left.IsSatisfiedBy(entity) || s.right.IsSatisfiedBy(entity)
 }
 
+// Left returns the left operand
+func (s *OrSpecification[T]) Left() Specification[T] {
+	return s.left
+}
+
+// Right returns the right operand
+func (s *OrSpecification[T]) Right() Specification[T] {
+	return s.right
+}
+
+// Explain evaluates both operands so that, when neither is satisfied, both are reported
+func (s *OrSpecification[T]) Explain(entity T) Result {
+	left := Explain(s.left, entity)
+	right := Explain(s.right, entity)
+	return Result{
+		Name:      string(OperatorOr),
+		Operator:  OperatorOr,
+		Satisfied: left.Satisfied || right.Satisfied,
+		Children:  []Result{left, right},
+	}
+}
+
 // NotSpecification negates a specification with logical NOT
 type NotSpecification[T any] struct {
 	spec Specification[T]
@@ -52,3 +96,18 @@ func (s *NotSpecification[T]) IsSatisfiedBy(entity T) bool {
 	return !s.spec.IsSatisfiedBy(entity)
 }
 
+// Spec returns the negated specification
+func (s *NotSpecification[T]) Spec() Specification[T] {
+	return s.spec
+}
+
+// Explain reports the negation itself as the violation, since its operand was satisfied
+func (s *NotSpecification[T]) Explain(entity T) Result {
+	inner := Explain(s.spec, entity)
+	return Result{
+		Name:      "not(" + inner.Name + ")",
+		Operator:  OperatorNot,
+		Satisfied: !inner.Satisfied,
+		Children:  []Result{inner},
+	}
+}
@@ -0,0 +1,104 @@
+package specification
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Composite definition types
+const (
+	DefinitionAnd = "and"
+	DefinitionOr  = "or"
+	DefinitionNot = "not"
+)
+
+// Definition is the JSON representation of a specification tree.
+// Composite nodes use the "and", "or" and "not" types with their operands in Specs,
+// while leaves use the name of a spec registered in a Registry and carry their Params.
+//
+// Example:
+//
+//	{"type": "and", "specs": [
+//	    {"type": "min_total", "params": {"amount": 100}},
+//	    {"type": "not", "specs": [{"type": "status_in", "params": {"statuses": ["cancelled"]}}]}
+//	]}
+type Definition struct {
+	Type   string          `json:"type"`
+	Specs  []Definition    `json:"specs,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// DefinitionProvider is implemented by specifications that know their own definition
+type DefinitionProvider interface {
+	Definition() Definition
+}
+
+// ToDefinition converts a specification tree back into its definition.
+// Leaves must implement DefinitionProvider, which is the case for every leaf built by a Registry.
+func ToDefinition[T any](spec Specification[T]) (Definition, error) {
+	switch s := spec.(type) {
+	case *BuilderSpecification[T]:
+		return ToDefinition(s.spec)
+	case *AndSpecification[T]:
+		return compositeDefinition(DefinitionAnd, s.left, s.right)
+	case *OrSpecification[T]:
+		return compositeDefinition(DefinitionOr, s.left, s.right)
+	case *NotSpecification[T]:
+		return compositeDefinition(DefinitionNot, s.spec)
+	case DefinitionProvider:
+		return s.Definition(), nil
+	default:
+		return Definition{}, fmt.Errorf("specification %T is not serializable", spec)
+	}
+}
+
+// Marshal encodes a specification tree as JSON
+func Marshal[T any](spec Specification[T]) ([]byte, error) {
+	def, err := ToDefinition(spec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(def)
+}
+
+func compositeDefinition[T any](typ string, operands ...Specification[T]) (Definition, error) {
+	def := Definition{Type: typ, Specs: make([]Definition, 0, len(operands))}
+	for _, operand := range operands {
+		child, err := ToDefinition(operand)
+		if err != nil {
+			return Definition{}, err
+		}
+		def.Specs = append(def.Specs, child)
+	}
+	return def, nil
+}
+
+// definedSpecification is a leaf built from a definition, remembering it for serialization
+type definedSpecification[T any] struct {
+	spec Specification[T]
+	def  Definition
+}
+
+func (d *definedSpecification[T]) IsSatisfiedBy(entity T) bool {
+	return d.spec.IsSatisfiedBy(entity)
+}
+
+// Explain delegates to the wrapped leaf, naming it after its definition type when it is not explainable
+func (d *definedSpecification[T]) Explain(entity T) Result {
+	if explainable, ok := d.spec.(ExplainableSpecification[T]); ok {
+		return explainable.Explain(entity)
+	}
+	return Result{
+		Name:      d.def.Type,
+		Satisfied: d.spec.IsSatisfiedBy(entity),
+	}
+}
+
+func (d *definedSpecification[T]) Definition() Definition {
+	return d.def
+}
+
+// Unwrap returns the leaf specification built by the registry factory
+func (d *definedSpecification[T]) Unwrap() Specification[T] {
+	return d.spec
+}
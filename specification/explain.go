@@ -0,0 +1,155 @@
+package specification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+)
+
+// ExplainableSpecification is a specification that can explain why an entity
+// does or does not satisfy it.
+type ExplainableSpecification[T any] interface {
+	Specification[T]
+	// Explain evaluates the specification and returns a result tree
+	Explain(entity T) Result
+}
+
+// Operator identifies the logical operator of a composite result node
+type Operator string
+
+const (
+	OperatorAnd Operator = "and"
+	OperatorOr  Operator = "or"
+	OperatorNot Operator = "not"
+)
+
+// Result is the outcome of evaluating a specification in explainable mode.
+// Composite specifications produce a node whose Children hold the results of their operands.
+type Result struct {
+	Name      string                `json:"name"`
+	Operator  Operator              `json:"operator,omitempty"`
+	Satisfied bool                  `json:"satisfied"`
+	Phrase    phrases.MessagePhrase `json:"phrase,omitempty"`
+	Args      []interface{}         `json:"args,omitempty"`
+	Children  []Result              `json:"children,omitempty"`
+}
+
+// Violations returns every unsatisfied rule in the result tree.
+// Leaves are reported directly; a failed NOT is reported as a whole since its operand was satisfied.
+func (r Result) Violations() []Result {
+	if r.Satisfied {
+		return nil
+	}
+	if r.Operator == "" || r.Operator == OperatorNot {
+		return []Result{r}
+	}
+
+	var violations []Result
+	for _, child := range r.Children {
+		violations = append(violations, child.Violations()...)
+	}
+	return violations
+}
+
+// Message returns the localised message of this result from the phrases registry
+func (r Result) Message(lan phrases.Language) string {
+	phrase := r.Phrase
+	if phrase == "" {
+		phrase = phrases.SpecificationNotSatisfied
+	}
+
+	message := phrases.GetMessage(phrase, lan)
+	if len(r.Args) > 0 {
+		message = fmt.Sprintf(message, r.Args...)
+	}
+	return message
+}
+
+// Error converts the violations of this result into a validation error, or nil when satisfied.
+// A single violation is reported with its own phrase and message; multiple violations are
+// reported as SpecificationNotSatisfied with every violation message listed in the detail.
+func (r Result) Error(lan phrases.Language) error {
+	violations := r.Violations()
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if len(violations) == 1 {
+		v := violations[0]
+		id := v.Phrase
+		if id == "" {
+			id = phrases.SpecificationNotSatisfied
+		}
+		return errors.New(string(id), errors.ErrorTypeValidation, v.Message(lan), "")
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message(lan))
+	}
+
+	return errors.New(
+		string(phrases.SpecificationNotSatisfied),
+		errors.ErrorTypeValidation,
+		phrases.GetMessage(phrases.SpecificationNotSatisfied, lan),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Explain evaluates spec against entity in explainable mode.
+// Specifications that do not implement ExplainableSpecification are reported as an
+// unnamed rule using their type name.
+func Explain[T any](spec Specification[T], entity T) Result {
+	if explainable, ok := spec.(ExplainableSpecification[T]); ok {
+		return explainable.Explain(entity)
+	}
+
+	return Result{
+		Name:      fmt.Sprintf("%T", spec),
+		Satisfied: spec.IsSatisfiedBy(entity),
+	}
+}
+
+// Rule is a named leaf specification carrying a message phrase that explains a violation
+type Rule[T any] struct {
+	name      string
+	phrase    phrases.MessagePhrase
+	args      []interface{}
+	predicate func(entity T) bool
+}
+
+// NewRule creates a named rule from a predicate.
+// args are used to format the phrase message when the rule is violated.
+func NewRule[T any](name string, phrase phrases.MessagePhrase, predicate func(entity T) bool, args ...interface{}) *Rule[T] {
+	return &Rule[T]{
+		name:      name,
+		phrase:    phrase,
+		args:      args,
+		predicate: predicate,
+	}
+}
+
+// Named wraps an existing specification as a named rule
+func Named[T any](name string, phrase phrases.MessagePhrase, spec Specification[T], args ...interface{}) *Rule[T] {
+	return NewRule(name, phrase, spec.IsSatisfiedBy, args...)
+}
+
+func (r *Rule[T]) IsSatisfiedBy(entity T) bool {
+	return r.predicate(entity)
+}
+
+func (r *Rule[T]) Explain(entity T) Result {
+	return Result{
+		Name:      r.name,
+		Satisfied: r.predicate(entity),
+		Phrase:    r.phrase,
+		Args:      r.args,
+	}
+}
+
+// Name returns the rule name
+func (r *Rule[T]) Name() string {
+	return r.name
+}
@@ -0,0 +1,252 @@
+package specification
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+)
+
+// FieldOperator is the comparison performed by a field specification
+type FieldOperator string
+
+const (
+	FieldEq                 FieldOperator = "eq"
+	FieldNotEq              FieldOperator = "not_eq"
+	FieldIn                 FieldOperator = "in"
+	FieldBetween            FieldOperator = "between"
+	FieldGreaterThan        FieldOperator = "gt"
+	FieldGreaterThanOrEqual FieldOperator = "gte"
+	FieldLessThan           FieldOperator = "lt"
+	FieldLessThanOrEqual    FieldOperator = "lte"
+	FieldContains           FieldOperator = "contains"
+	FieldIsNull             FieldOperator = "is_null"
+)
+
+// DefinitionField is the definition type of field specifications
+const DefinitionField = "field"
+
+// FieldSpecification exposes the metadata of a field comparison so that other
+// backends (SQL, Elasticsearch, ...) can translate it instead of evaluating it in memory.
+type FieldSpecification interface {
+	// Field returns the dotted field path, e.g. "Customer.Address.City"
+	Field() string
+	// Operator returns the comparison operator
+	Operator() FieldOperator
+	// Values returns the operands of the comparison (one for most operators,
+	// two for Between, any number for In and none for IsNull)
+	Values() []interface{}
+}
+
+// FieldSpec is a generic leaf specification comparing a struct field, addressed by path,
+// against constant values. Path segments match Go field names or json tag names, and
+// pointers, embedded structs and string-keyed maps are followed along the path.
+type FieldSpec[T any] struct {
+	field    string
+	operator FieldOperator
+	values   []interface{}
+}
+
+// NewFieldSpec creates a field specification with an arbitrary operator
+func NewFieldSpec[T any](field string, operator FieldOperator, values ...interface{}) *FieldSpec[T] {
+	return &FieldSpec[T]{
+		field:    field,
+		operator: operator,
+		values:   values,
+	}
+}
+
+// Eq is satisfied when the field equals value
+func Eq[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldEq, value)
+}
+
+// NotEq is satisfied when the field does not equal value
+func NotEq[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldNotEq, value)
+}
+
+// In is satisfied when the field equals any of values
+func In[T any](field string, values ...interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldIn, values...)
+}
+
+// Between is satisfied when min <= field <= max
+func Between[T any](field string, min, max interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldBetween, min, max)
+}
+
+// GreaterThan is satisfied when field > value
+func GreaterThan[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldGreaterThan, value)
+}
+
+// GreaterThanOrEqual is satisfied when field >= value
+func GreaterThanOrEqual[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldGreaterThanOrEqual, value)
+}
+
+// LessThan is satisfied when field < value
+func LessThan[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldLessThan, value)
+}
+
+// LessThanOrEqual is satisfied when field <= value
+func LessThanOrEqual[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldLessThanOrEqual, value)
+}
+
+// Contains is satisfied when a string field contains the substring value,
+// or when a slice field contains an element equal to value
+func Contains[T any](field string, value interface{}) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldContains, value)
+}
+
+// IsNull is satisfied when the field (or any pointer along its path) is nil
+func IsNull[T any](field string) *FieldSpec[T] {
+	return NewFieldSpec[T](field, FieldIsNull)
+}
+
+// Before is satisfied when a time field is strictly before t
+func Before[T any](field string, t time.Time) *FieldSpec[T] {
+	return LessThan[T](field, t)
+}
+
+// After is satisfied when a time field is strictly after t
+func After[T any](field string, t time.Time) *FieldSpec[T] {
+	return GreaterThan[T](field, t)
+}
+
+// DateRange is satisfied when from <= field <= to
+func DateRange[T any](field string, from, to time.Time) *FieldSpec[T] {
+	return Between[T](field, from, to)
+}
+
+func (s *FieldSpec[T]) Field() string           { return s.field }
+func (s *FieldSpec[T]) Operator() FieldOperator { return s.operator }
+func (s *FieldSpec[T]) Values() []interface{}   { return s.values }
+
+func (s *FieldSpec[T]) IsSatisfiedBy(entity T) bool {
+	value, found, isNull := resolveField(entity, s.field)
+	if !found {
+		return false
+	}
+
+	if s.operator == FieldIsNull {
+		return isNull
+	}
+	if isNull {
+		return s.operator == FieldNotEq
+	}
+
+	switch s.operator {
+	case FieldEq:
+		return s.arity(1) && valuesEqual(value, s.values[0])
+	case FieldNotEq:
+		return s.arity(1) && !valuesEqual(value, s.values[0])
+	case FieldIn:
+		for _, candidate := range s.values {
+			if valuesEqual(value, candidate) {
+				return true
+			}
+		}
+		return false
+	case FieldBetween:
+		if !s.arity(2) {
+			return false
+		}
+		lower, ok := compareValues(value, s.values[0])
+		if !ok || lower < 0 {
+			return false
+		}
+		upper, ok := compareValues(value, s.values[1])
+		return ok && upper <= 0
+	case FieldGreaterThan, FieldGreaterThanOrEqual, FieldLessThan, FieldLessThanOrEqual:
+		if !s.arity(1) {
+			return false
+		}
+		cmp, ok := compareValues(value, s.values[0])
+		if !ok {
+			return false
+		}
+		switch s.operator {
+		case FieldGreaterThan:
+			return cmp > 0
+		case FieldGreaterThanOrEqual:
+			return cmp >= 0
+		case FieldLessThan:
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case FieldContains:
+		return s.arity(1) && containsValue(value, s.values[0])
+	default:
+		return false
+	}
+}
+
+// Explain reports the field comparison as a named rule, e.g. "Total gte"
+func (s *FieldSpec[T]) Explain(entity T) Result {
+	return Result{
+		Name:      s.field + " " + string(s.operator),
+		Satisfied: s.IsSatisfiedBy(entity),
+	}
+}
+
+// fieldParams are the JSON parameters of a field specification definition
+type fieldParams struct {
+	Field    string        `json:"field" validate:"required"`
+	Operator FieldOperator `json:"operator" validate:"required"`
+	Values   []interface{} `json:"values,omitempty"`
+}
+
+// Definition returns the JSON definition of the field specification
+func (s *FieldSpec[T]) Definition() Definition {
+	params, _ := json.Marshal(fieldParams{
+		Field:    s.field,
+		Operator: s.operator,
+		Values:   s.values,
+	})
+	return Definition{Type: DefinitionField, Params: params}
+}
+
+func (s *FieldSpec[T]) arity(n int) bool {
+	return len(s.values) == n
+}
+
+// RegisterFieldSpecifications registers the "field" leaf so field specifications can be
+// decoded from definitions such as {"type": "field", "params": {"field": "total", "operator": "gte", "values": [100]}}
+func RegisterFieldSpecifications[T any](r *Registry[T]) error {
+	return RegisterLeaf(r, DefinitionField, func(p fieldParams) (Specification[T], error) {
+		if err := validateFieldArity(p.Operator, len(p.Values)); err != nil {
+			return nil, err
+		}
+		return NewFieldSpec[T](p.Field, p.Operator, p.Values...), nil
+	})
+}
+
+func validateFieldArity(operator FieldOperator, n int) error {
+	var valid bool
+	switch operator {
+	case FieldEq, FieldNotEq, FieldGreaterThan, FieldGreaterThanOrEqual,
+		FieldLessThan, FieldLessThanOrEqual, FieldContains:
+		valid = n == 1
+	case FieldBetween:
+		valid = n == 2
+	case FieldIn:
+		valid = n > 0
+	case FieldIsNull:
+		valid = n == 0
+	default:
+		return errors.Validation(phrases.SpecificationInvalidStructure, fmt.Sprintf("unknown field operator %s", operator))
+	}
+
+	if !valid {
+		return errors.Validation(phrases.SpecificationInvalidStructure,
+			fmt.Sprintf("operator %s does not accept %d values", operator, n))
+	}
+	return nil
+}
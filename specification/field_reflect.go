@@ -0,0 +1,247 @@
+package specification
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// resolveField walks a dotted path through structs, pointers and string-keyed maps.
+// found is false when a segment does not exist; isNull is true when a nil pointer,
+// interface, map or slice is reached along the path or at its end.
+func resolveField(entity interface{}, path string) (value reflect.Value, found bool, isNull bool) {
+	value = reflect.ValueOf(entity)
+
+	for _, segment := range strings.Split(path, ".") {
+		var ok bool
+		if value, ok = indirect(value); !ok {
+			return reflect.Value{}, true, true
+		}
+
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := structField(value, segment)
+			if !ok {
+				return reflect.Value{}, false, false
+			}
+			value = field
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false, false
+			}
+			value = value.MapIndex(reflect.ValueOf(segment).Convert(value.Type().Key()))
+			if !value.IsValid() {
+				return reflect.Value{}, true, true
+			}
+		default:
+			return reflect.Value{}, false, false
+		}
+	}
+
+	value, ok := indirect(value)
+	if !ok {
+		return reflect.Value{}, true, true
+	}
+	if (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.IsNil() {
+		return value, true, true
+	}
+	return value, true, false
+}
+
+// indirect dereferences pointers and interfaces, reporting false when a nil is reached
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, value.IsValid()
+}
+
+// structField finds an exported field by Go name or json tag name, including promoted fields
+func structField(value reflect.Value, name string) (reflect.Value, bool) {
+	if field, ok := value.Type().FieldByName(name); ok && field.IsExported() {
+		return fieldByIndex(value, field.Index)
+	}
+
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == name {
+			return fieldByIndex(value, field.Index)
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// FieldType returns the type of the field a dotted path addresses in T, following the same
+// rules as field specifications, with pointers dereferenced. It reports false for unknown
+// paths and for fields whose type is only known at runtime, such as interfaces.
+func FieldType[T any](path string) (reflect.Type, bool) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	for _, segment := range strings.Split(path, ".") {
+		t = indirectType(t)
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := structFieldType(t, segment)
+			if !ok {
+				return nil, false
+			}
+			t = field.Type
+		case reflect.Map:
+			if t.Key().Kind() != reflect.String {
+				return nil, false
+			}
+			t = t.Elem()
+		default:
+			return nil, false
+		}
+	}
+
+	t = indirectType(t)
+	return t, t.Kind() != reflect.Interface
+}
+
+// indirectType dereferences pointer types
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// structFieldType is the type-level counterpart of structField
+func structFieldType(t reflect.Type, name string) (reflect.StructField, bool) {
+	if field, ok := t.FieldByName(name); ok && field.IsExported() {
+		return field, true
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but does not panic on nil embedded pointers
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 {
+			var ok bool
+			if value, ok = indirect(value); !ok {
+				return reflect.Value{}, false
+			}
+		}
+		value = value.Field(idx)
+	}
+	return value, true
+}
+
+// valuesEqual compares a resolved field with a constant, normalising numbers, strings and times
+func valuesEqual(field reflect.Value, operand interface{}) bool {
+	if cmp, ok := compareValues(field, operand); ok {
+		return cmp == 0
+	}
+
+	other, ok := indirect(reflect.ValueOf(operand))
+	if !ok {
+		return false
+	}
+	if field.Kind() == reflect.Bool && other.Kind() == reflect.Bool {
+		return field.Bool() == other.Bool()
+	}
+	return reflect.DeepEqual(field.Interface(), other.Interface())
+}
+
+// compareValues orders a resolved field against a constant.
+// It returns false when the two values are not ordered types of the same family.
+func compareValues(field reflect.Value, operand interface{}) (int, bool) {
+	other, ok := indirect(reflect.ValueOf(operand))
+	if !ok {
+		return 0, false
+	}
+
+	if field.Type() == timeType {
+		t, ok := toTime(other)
+		if !ok {
+			return 0, false
+		}
+		return field.Interface().(time.Time).Compare(t), true
+	}
+
+	if a, ok := toNumber(field); ok {
+		b, ok := toNumber(other)
+		if !ok {
+			return 0, false
+		}
+		return a.Cmp(b), true
+	}
+
+	if field.Kind() == reflect.String && other.Kind() == reflect.String {
+		return strings.Compare(field.String(), other.String()), true
+	}
+
+	return 0, false
+}
+
+// containsValue checks substring containment for strings and element membership for slices
+func containsValue(field reflect.Value, operand interface{}) bool {
+	switch field.Kind() {
+	case reflect.String:
+		other, ok := indirect(reflect.ValueOf(operand))
+		return ok && other.Kind() == reflect.String && strings.Contains(field.String(), other.String())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			element, ok := indirect(field.Index(i))
+			if ok && valuesEqual(element, operand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toNumber converts any integer or float kind into a big.Float for exact comparison
+func toNumber(value reflect.Value) (*big.Float, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(value.Float()) {
+			return nil, false
+		}
+		return new(big.Float).SetFloat64(value.Float()), true
+	}
+	return nil, false
+}
+
+// toTime accepts time.Time values and RFC3339 or date-only strings (as decoded from JSON definitions)
+func toTime(value reflect.Value) (time.Time, bool) {
+	if value.Type() == timeType {
+		return value.Interface().(time.Time), true
+	}
+	if value.Kind() != reflect.String {
+		return time.Time{}, false
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, value.String()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
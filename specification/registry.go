@@ -0,0 +1,184 @@
+package specification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors"
+	"github.com/ali-mahdavi-dev/shikposh-framework/errors/phrases"
+	"github.com/ali-mahdavi-dev/shikposh-framework/helpers/validation"
+)
+
+// LeafFactory builds a leaf specification from its raw JSON parameters
+type LeafFactory[T any] func(params json.RawMessage) (Specification[T], error)
+
+// Registry maps leaf names to factories so specification definitions can be decoded
+// back into Specification[T] at runtime (e.g. stored promotion rules or saved searches).
+type Registry[T any] struct {
+	mu        sync.RWMutex
+	factories map[string]LeafFactory[T]
+}
+
+// NewRegistry creates an empty specification registry
+func NewRegistry[T any]() *Registry[T] {
+	return &Registry[T]{
+		factories: make(map[string]LeafFactory[T]),
+	}
+}
+
+// Register registers a leaf factory under the given name
+func (r *Registry[T]) Register(name string, factory LeafFactory[T]) error {
+	if name == DefinitionAnd || name == DefinitionOr || name == DefinitionNot {
+		return errors.Conflict("", fmt.Sprintf("specification name %s is reserved", name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[name]; ok {
+		return errors.Conflict("", fmt.Sprintf("specification %s already registered", name))
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// RegisterLeaf registers a leaf whose parameters are decoded into P.
+// Unknown parameter fields are rejected and P is validated using `validate` struct tags,
+// including custom rules registered on validation.Validator().
+func RegisterLeaf[T any, P any](r *Registry[T], name string, build func(params P) (Specification[T], error)) error {
+	return r.Register(name, func(raw json.RawMessage) (Specification[T], error) {
+		var params P
+		if len(raw) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&params); err != nil {
+				return nil, err
+			}
+		}
+		if err := validateParams(params); err != nil {
+			return nil, err
+		}
+		return build(params)
+	})
+}
+
+// Names returns the sorted names of all registered leaves
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode decodes a JSON specification definition into a specification tree
+func (r *Registry[T]) Decode(data []byte) (Specification[T], error) {
+	var def Definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, errors.Validation(phrases.SpecificationInvalidStructure, err.Error())
+	}
+	return r.Build(def)
+}
+
+// Build builds a specification tree from a definition.
+// Unknown leaves, bad parameters and malformed composites return validation errors
+// describing the path of the offending node (e.g. "$.specs[1].specs[0]").
+func (r *Registry[T]) Build(def Definition) (Specification[T], error) {
+	return r.build(def, "$")
+}
+
+// Validate checks that a definition can be built without returning the specification
+func (r *Registry[T]) Validate(def Definition) error {
+	_, err := r.Build(def)
+	return err
+}
+
+func (r *Registry[T]) build(def Definition, path string) (Specification[T], error) {
+	switch def.Type {
+	case DefinitionAnd, DefinitionOr:
+		if len(def.Specs) == 0 {
+			return nil, errors.Validation(phrases.SpecificationInvalidStructure,
+				fmt.Sprintf("%s: %s requires at least one operand", path, def.Type))
+		}
+
+		operands, err := r.buildOperands(def.Specs, path)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := operands[0]
+		for _, operand := range operands[1:] {
+			if def.Type == DefinitionAnd {
+				spec = NewAndSpecification(spec, operand)
+			} else {
+				spec = NewOrSpecification(spec, operand)
+			}
+		}
+		return spec, nil
+
+	case DefinitionNot:
+		if len(def.Specs) != 1 {
+			return nil, errors.Validation(phrases.SpecificationInvalidStructure,
+				fmt.Sprintf("%s: not requires exactly one operand", path))
+		}
+
+		operands, err := r.buildOperands(def.Specs, path)
+		if err != nil {
+			return nil, err
+		}
+		return NewNotSpecification(operands[0]), nil
+
+	case "":
+		return nil, errors.Validation(phrases.SpecificationInvalidStructure,
+			fmt.Sprintf("%s: type is required", path))
+	}
+
+	if len(def.Specs) > 0 {
+		return nil, errors.Validation(phrases.SpecificationInvalidStructure,
+			fmt.Sprintf("%s: leaf %s cannot have operands", path, def.Type))
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[def.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, errors.Validation(phrases.SpecificationUnknownType, fmt.Sprintf("%s (at %s)", def.Type, path))
+	}
+
+	spec, err := factory(def.Params)
+	if err != nil {
+		return nil, errors.Validation(phrases.SpecificationInvalidParams, def.Type, fmt.Sprintf("%s (at %s)", err.Error(), path))
+	}
+
+	return &definedSpecification[T]{spec: spec, def: def}, nil
+}
+
+func (r *Registry[T]) buildOperands(defs []Definition, path string) ([]Specification[T], error) {
+	operands := make([]Specification[T], 0, len(defs))
+	for i, child := range defs {
+		operand, err := r.build(child, fmt.Sprintf("%s.specs[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	return operands, nil
+}
+
+// validateParams validates struct parameters; non-struct parameters are accepted as decoded
+func validateParams(params any) error {
+	err := validation.Struct(params)
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		return nil
+	}
+	return err
+}
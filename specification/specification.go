@@ -27,6 +27,11 @@ func (b *BuilderSpecification[T]) IsSatisfiedBy(entity T) bool {
 	return b.spec.IsSatisfiedBy(entity)
 }
 
+// Explain delegates to the wrapped specification in explainable mode
+func (b *BuilderSpecification[T]) Explain(entity T) Result {
+	return Explain(b.spec, entity)
+}
+
 // And combines this specification with another using logical AND
 func (b *BuilderSpecification[T]) And(other Specification[T]) *BuilderSpecification[T] {
 	return NewBuilder(NewAndSpecification(b.spec, other))